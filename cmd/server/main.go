@@ -3,22 +3,40 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
-	glm5agent "r3f-trends/internal/adapter/driven/agent/glm5"
+	"r3f-trends/internal/adapter/driven/agent"
+	"r3f-trends/internal/adapter/driven/agent/cache"
+	"r3f-trends/internal/adapter/driven/agent/llm"
 	chromecollector "r3f-trends/internal/adapter/driven/collector/chrome"
+	"r3f-trends/internal/adapter/driven/collector/fixture"
 	httpcollector "r3f-trends/internal/adapter/driven/collector/http"
 	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/content"
+	"r3f-trends/internal/adapter/driven/embedding"
+	"r3f-trends/internal/adapter/driven/mail"
 	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/adapter/driven/webhook"
+	"r3f-trends/internal/app/digest"
+	"r3f-trends/internal/app/exchange"
+	"r3f-trends/internal/app/feed"
+	"r3f-trends/internal/app/logging"
+	"r3f-trends/internal/app/metrics"
 	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
 	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/event"
 )
 
 func main() {
@@ -30,12 +48,134 @@ func main() {
 	cfgLoader := yaml.NewConfigLoader(configPath)
 	cfg, err := cfgLoader.Load()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("failed to load config", err)
+	}
+	logger, err := logging.New(os.Stderr, cfg.Logging.Level, cfg.Logging.Format)
+	if err != nil {
+		fatal("failed to set up logging", err)
+	}
+	slog.SetDefault(logger)
+
+	profileLoader := yaml.NewProfileLoader(configPath + "/profiles")
+	if _, err := profileLoader.LoadAll(context.Background()); err != nil {
+		fatal("failed to load profiles", err)
 	}
 
 	trendRepo := markdown.NewTrendRepositoryAdapter(cfg.Storage.BasePath)
-	httpCollector := httpcollector.New()
-	chromeCollector := chromecollector.New()
+	httpCollector, chromeCollector, err := newCollectors(cfg.Fixtures)
+	if err != nil {
+		fatal("failed to set up collectors", err)
+	}
+
+	dispatcher := event.NewDispatcher()
+	eventStream := service.NewEventStream(service.DefaultEventBacklog)
+	eventStream.Subscribe(dispatcher)
+
+	trendSvc := service.NewTrendService(trendRepo, service.WithTrendDispatcher(dispatcher))
+	suggestionSvc := service.NewSuggestionService(markdown.NewSuggestionRepository(cfg.Storage.BasePath), profileLoader, cfg.LLM.SuggestionHistory)
+
+	var (
+		agentSvc *service.AgentService
+		llmCache *cache.Agent
+	)
+	usageSvc, err := newUsageService(cfg)
+	if err != nil {
+		fatal("failed to set up usage accounting", err)
+	}
+
+	llmAgent, err := newLLMAgent(cfg.LLM, llm.WithUsageRecorder(usageSvc))
+	if err != nil {
+		slog.Warn("LLM agent disabled", "error", err)
+	} else {
+		agentOpts := []service.AgentOption{
+			service.WithSummaryHistory(cfg.LLM.SummaryHistory),
+			service.WithUsage(usageSvc),
+			service.WithSuggestions(suggestionSvc),
+			service.WithDrafts(markdown.NewDraftRepository(cfg.Storage.BasePath)),
+		}
+		if cfg.Content.Enabled {
+			fetcher, err := newContentFetcher(cfg)
+			if err != nil {
+				fatal("failed to set up content fetcher", err)
+			}
+			agentOpts = append(agentOpts, service.WithContentFetcher(fetcher, cfg.Content.ChunkChars))
+		}
+		var backend service.LLMAgent = llmAgent
+		if cfg.LLM.Cache.Enabled {
+			llmCache, err = newLLMCache(cfg, llmAgent)
+			if err != nil {
+				fatal("failed to set up LLM cache", err)
+			}
+			backend = llmCache
+		}
+		agentSvc = service.NewAgentService(backend, trendSvc, profileLoader, cfg.ActiveProfile, agentOpts...)
+	}
+
+	dispatcher.Subscribe("alert.raised", func(e event.Event) {
+		alert := e.(*event.AlertRaisedEvent)
+		slog.Info("alert raised", "rule", alert.Rule, "title", alert.Title, "trend_id", alert.TrendID)
+	})
+	webhookSvc, err := newWebhookService(cfg)
+	if err != nil {
+		fatal("failed to set up webhooks", err)
+	}
+	webhookSvc.Subscribe(dispatcher)
+
+	var (
+		collectorOpts = []service.CollectorOption{service.WithCollectorDispatcher(dispatcher)}
+		embeddingSvc  *service.EmbeddingService
+	)
+	if cfg.Embedding.Enabled {
+		embeddingSvc, err = newEmbeddingService(cfg, trendSvc)
+		if err != nil {
+			fatal("failed to set up embeddings", err)
+		}
+	}
+
+	var clusterSvc *service.ClusterService
+	if cfg.Clustering.Enabled {
+		var clusterOpts []service.ClusterOption
+		if embeddingSvc != nil {
+			clusterOpts = append(clusterOpts, service.WithClusterEmbeddings(embeddingSvc))
+		}
+		if cfg.Clustering.NameWithLLM && agentSvc != nil {
+			clusterOpts = append(clusterOpts, service.WithClusterNamer(agentSvc))
+		}
+		clusterSvc = service.NewClusterService(trendSvc, markdown.NewClusterRepository(cfg.Storage.BasePath), service.ClusterConfig{
+			Threshold:    cfg.Clustering.Threshold,
+			LookbackDays: cfg.Clustering.LookbackDays,
+		}, clusterOpts...)
+	}
+
+	if cfg.Enrichment.Enabled {
+		if agentSvc == nil {
+			slog.Warn("enrichment disabled: no LLM agent configured")
+		} else {
+			collectorOpts = append(collectorOpts, service.WithStages(service.NewEnrichmentService(agentSvc, trendRepo, service.EnrichmentConfig{
+				Summarize:   cfg.Enrichment.Summarize,
+				Tag:         cfg.Enrichment.Tag,
+				Concurrency: cfg.Enrichment.Concurrency,
+				MaxPerRun:   cfg.Enrichment.MaxPerRun,
+			})))
+		}
+	}
+	// Keywords, embeddings and clusters use the summaries and tags added
+	// above.
+	var keywordSvc *service.KeywordService
+	if cfg.Keywords.Enabled {
+		keywordSvc = service.NewKeywordService(trendSvc, service.KeywordConfig{PerTrend: cfg.Keywords.PerTrend})
+		collectorOpts = append(collectorOpts, service.WithStages(keywordSvc))
+	}
+	if embeddingSvc != nil {
+		collectorOpts = append(collectorOpts, service.WithStages(embeddingSvc))
+	}
+	if clusterSvc != nil {
+		collectorOpts = append(collectorOpts, service.WithStages(clusterSvc))
+	}
+
+	watchSvc := service.NewWatchService(profileLoader, trendSvc, markdown.NewAlertRepository(cfg.Storage.BasePath),
+		service.WithAlertDispatcher(dispatcher))
+	collectorOpts = append(collectorOpts, service.WithStages(watchSvc))
 
 	collectorSvc := service.NewCollectorService(
 		trendRepo,
@@ -43,50 +183,98 @@ func main() {
 			"http":   httpCollector,
 			"chrome": chromeCollector,
 		},
+		collectorOpts...,
 	)
 
-	trendSvc := service.NewTrendService(trendRepo)
+	var digestSvc *service.DigestService
+	if cfg.Digest.Enabled {
+		digestSvc, err = newDigestService(cfg, configPath, trendSvc, profileLoader, clusterSvc)
+		if err != nil {
+			fatal("failed to set up digests", err)
+		}
+	}
 
-	var agentSvc *service.AgentService
-	if cfg.LLM.APIKey != "" {
-		llmAgent := glm5agent.NewAgent(cfg.LLM.APIKey, cfg.LLM.BaseURL)
-		agentSvc = service.NewAgentService(llmAgent, trendSvc)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.Scheduler.Enabled {
+		scheduler, err := newScheduler(cfg, configPath, collectorSvc, digestSvc)
+		if err != nil {
+			fatal("failed to set up scheduler", err)
+		}
+		go scheduler.Run(schedulerCtx)
 	}
 
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/api/v1/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	llmCheck := service.HealthCheck{Name: "llm"}
+	if agentSvc != nil {
+		llmCheck.Detail = fmt.Sprintf("%s (%s)", llmAgent.Name(), llmAgent.Model())
+		llmCheck.Check = llmAgent.Ping
+	}
+	healthSvc := service.NewHealthService(collectorSvc, []service.HealthCheck{
+		{Name: "storage", Required: true, Detail: cfg.Storage.BasePath, Check: trendRepo.CheckWritable},
+		{Name: "chrome", Check: chromeCollector.CheckLaunch},
+		llmCheck,
 	})
+	mux.HandleFunc("/api/v1/health", healthHandler(healthSvc))
+	mux.Handle("/metrics", metrics.Default.Handler())
 
+	mux.HandleFunc("/api/v1/events", eventsHandler(eventStream))
 	mux.HandleFunc("/api/v1/trends", trendsHandler(trendSvc))
-	mux.HandleFunc("/api/v1/trends/", trendDetailHandler(trendSvc))
+	mux.HandleFunc("/api/v1/trends/search", trendSearchHandler(trendSvc, embeddingSvc))
+	mux.HandleFunc("/api/v1/trends/", trendDetailHandler(trendSvc, embeddingSvc))
 	mux.HandleFunc("/api/v1/collect", collectHandler(collectorSvc, configPath, cfg.ActiveProfile))
 	mux.HandleFunc("/api/v1/sources", sourcesHandler(configPath))
 	mux.HandleFunc("/api/v1/profiles", profilesHandler(configPath))
+	exchangeSvc := service.NewExchangeService(trendRepo, profileLoader, yaml.NewSourceLoader(configPath+"/sources"))
+	mux.HandleFunc("/api/v1/export", exportHandler(exchangeSvc, cfg.ActiveProfile))
+	mux.HandleFunc("/api/v1/import", importHandler(exchangeSvc))
+	mux.HandleFunc("/api/v1/sources/opml", opmlHandler(exchangeSvc, cfg.ActiveProfile))
+	if digestSvc != nil {
+		mux.HandleFunc("/api/v1/digests", digestsHandler(digestSvc, cfg.ActiveProfile))
+	}
+	mux.HandleFunc("/api/v1/feeds/", feedsHandler(service.NewFeedService(trendSvc, profileLoader)))
+	mux.HandleFunc("/api/v1/webhooks/test", webhookTestHandler(webhookSvc))
+	mux.HandleFunc("/api/v1/webhooks/dead-letters", deadLettersHandler(webhookSvc))
+	mux.HandleFunc("/api/v1/alerts", alertsHandler(watchSvc, cfg.ActiveProfile))
+	mux.HandleFunc("/api/v1/alerts/seen", alertsSeenHandler(watchSvc, cfg.ActiveProfile))
+	if keywordSvc != nil {
+		mux.HandleFunc("/api/v1/keywords", keywordsHandler(keywordSvc))
+	}
+	if clusterSvc != nil {
+		mux.HandleFunc("/api/v1/clusters", clustersHandler(clusterSvc))
+	}
 
 	if agentSvc != nil {
 		mux.HandleFunc("/api/v1/agent/summarize", agentSummarizeHandler(agentSvc))
-		mux.HandleFunc("/api/v1/agent/suggest", agentSuggestHandler(agentSvc))
+		mux.HandleFunc("/api/v1/agent/suggest", agentSuggestHandler(agentSvc, cfg.ActiveProfile))
+		mux.HandleFunc("/api/v1/agent/draft", agentDraftHandler(agentSvc, cfg.ActiveProfile))
+	}
+	mux.HandleFunc("/api/v1/agent/usage", agentUsageHandler(usageSvc))
+	mux.HandleFunc("/api/v1/agent/suggestions", suggestionsHandler(suggestionSvc, cfg.ActiveProfile))
+	mux.HandleFunc("/api/v1/agent/suggestions/", suggestionDetailHandler(suggestionSvc, cfg.ActiveProfile))
+	if llmCache != nil {
+		mux.HandleFunc("/api/v1/agent/cache", agentCacheHandler(llmCache))
 	}
 
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	srv := &http.Server{
 		Addr:         addr,
-		Handler:      mux,
+		Handler:      instrument(mux),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 60 * time.Second,
 	}
+	// Shutdown waits for open requests, so event streams are ended first.
+	srv.RegisterOnShutdown(eventStream.Close)
 
 	go func() {
-		log.Printf("Starting server on %s", addr)
-		log.Printf("Chrome collector: enabled")
+		attrs := []any{"addr", addr}
 		if agentSvc != nil {
-			log.Printf("GLM-5 agent: enabled")
+			attrs = append(attrs, "llm_provider", llmAgent.Name(), "llm_model", llmAgent.Model())
 		}
+		slog.Info("starting server", attrs...)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+			fatal("server error", err)
 		}
 	}()
 
@@ -94,12 +282,305 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	srv.Shutdown(ctx)
-	log.Println("Server stopped")
+	slog.Info("server stopped")
+}
+
+func newCollectors(cfg yaml.FixturesConfig) (*httpcollector.HTTPCollector, *chromecollector.ChromeCollector, error) {
+	mode, err := fixture.ParseMode(cfg.Mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch mode {
+	case fixture.ModeRecord:
+		slog.Info("recording collector fixtures", "dir", cfg.Dir)
+		return httpcollector.New(httpcollector.WithTransport(fixture.NewRecorder(cfg.Dir, nil))),
+			chromecollector.New(chromecollector.WithPageRecorder(fixture.PageRecorder(cfg.Dir))),
+			nil
+	case fixture.ModeReplay:
+		slog.Info("replaying collector fixtures", "dir", cfg.Dir)
+		pages := fixture.NewStaticServer(cfg.Dir)
+		return httpcollector.New(httpcollector.WithTransport(fixture.NewReplayer(cfg.Dir))),
+			chromecollector.New(chromecollector.WithURLRewriter(pages.Rewrite), chromecollector.WithSettleDelay(0)),
+			nil
+	default:
+		return httpcollector.New(), chromecollector.New(), nil
+	}
+}
+
+func newContentFetcher(cfg *yaml.Config) (*content.Fetcher, error) {
+	var ttl time.Duration
+	if cfg.Content.CacheTTL != "" {
+		d, err := time.ParseDuration(cfg.Content.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content.cache_ttl: %w", err)
+		}
+		ttl = d
+	}
+
+	opts := []content.Option{
+		content.WithMaxBytes(cfg.Content.MaxBytes),
+		content.WithMaxChars(cfg.Content.MaxChars),
+		content.WithCache(filepath.Join(cfg.Storage.BasePath, ".cache", "content"), ttl),
+	}
+
+	mode, err := fixture.ParseMode(cfg.Fixtures.Mode)
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case fixture.ModeRecord:
+		opts = append(opts, content.WithTransport(fixture.NewRecorder(cfg.Fixtures.Dir, nil)))
+	case fixture.ModeReplay:
+		opts = append(opts, content.WithTransport(fixture.NewReplayer(cfg.Fixtures.Dir)))
+	}
+
+	return content.New(opts...), nil
+}
+
+func newLLMCache(cfg *yaml.Config, next service.LLMAgent) (*cache.Agent, error) {
+	var ttl time.Duration
+	if cfg.LLM.Cache.TTL != "" {
+		d, err := time.ParseDuration(cfg.LLM.Cache.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm.cache.ttl: %w", err)
+		}
+		ttl = d
+	}
+
+	return cache.New(next, filepath.Join(cfg.Storage.BasePath, ".cache", "llm"),
+		cache.WithTTL(ttl),
+		cache.WithMaxBytes(cfg.LLM.Cache.MaxBytes),
+	)
+}
+
+func newDigestService(cfg *yaml.Config, configPath string, trendSvc *service.TrendService, profiles service.ProfileLoader, clusterSvc *service.ClusterService) (*service.DigestService, error) {
+	templates := service.DigestTemplates{Subject: cfg.Digest.Subject}
+	for _, t := range []struct {
+		path string
+		dst  *string
+	}{
+		{cfg.Digest.Templates.Markdown, &templates.Markdown},
+		{cfg.Digest.Templates.HTML, &templates.HTML},
+	} {
+		if t.path == "" {
+			continue
+		}
+		path := t.path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(configPath, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("digest template: %w", err)
+		}
+		*t.dst = string(data)
+	}
+	if err := digest.Validate(templates.Subject, templates.Markdown, templates.HTML); err != nil {
+		return nil, fmt.Errorf("digest template %w", err)
+	}
+
+	var opts []service.DigestOption
+	if clusterSvc != nil {
+		opts = append(opts, service.WithDigestClusters(clusterSvc))
+	}
+	if email := cfg.Digest.Email; email.Host != "" {
+		opts = append(opts, service.WithMailer(mail.NewSMTP(mail.Config{
+			Host:     email.Host,
+			Port:     email.Port,
+			Username: email.Username,
+			Password: email.Password,
+			From:     email.From,
+		})))
+	}
+
+	return service.NewDigestService(trendSvc, profiles, markdown.NewDigestRepository(cfg.Storage.BasePath), service.DigestConfig{
+		Limit:     cfg.Digest.Limit,
+		Templates: templates,
+		To:        cfg.Digest.Email.To,
+	}, opts...), nil
+}
+
+// newScheduler collects every scheduler.interval and, if digest.schedule is
+// set, generates the digest of the last digest.days complete days.
+func newScheduler(cfg *yaml.Config, configPath string, collectorSvc *service.CollectorService, digestSvc *service.DigestService) (*service.Scheduler, error) {
+	loc := time.Local
+	if cfg.Scheduler.Timezone != "" {
+		l, err := time.LoadLocation(cfg.Scheduler.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduler.timezone: %w", err)
+		}
+		loc = l
+	}
+	scheduler := service.NewScheduler(loc)
+
+	if cfg.Scheduler.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Scheduler.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduler.interval: %w", err)
+		}
+		err = scheduler.Every("collect", interval, func(ctx context.Context) error {
+			result, err := collectAll(ctx, collectorSvc, configPath, cfg.ActiveProfile)
+			if err == nil {
+				slog.InfoContext(ctx, "scheduled collection done", "items", len(result.Trends), "errors", len(result.Errors))
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if digestSvc != nil && cfg.Digest.Schedule != "" {
+		days := max(cfg.Digest.Days, 1)
+		err := scheduler.Daily("digest", cfg.Digest.Schedule, func(ctx context.Context) error {
+			yesterday := time.Now().In(loc).AddDate(0, 0, -1)
+			d, err := digestSvc.Generate(ctx, service.DigestRequest{
+				Profile: cfg.ActiveProfile,
+				From:    yesterday.AddDate(0, 0, -(days - 1)).Format("2006-01-02"),
+				To:      yesterday.Format("2006-01-02"),
+				Send:    cfg.Digest.Send,
+			})
+			if err == nil {
+				slog.InfoContext(ctx, "scheduled digest done", "path", d.Path)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scheduler, nil
+}
+
+func newWebhookService(cfg *yaml.Config) (*service.WebhookService, error) {
+	var backoff, timeout time.Duration
+	if cfg.Webhooks.Backoff != "" {
+		d, err := time.ParseDuration(cfg.Webhooks.Backoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhooks.backoff: %w", err)
+		}
+		backoff = d
+	}
+	if cfg.Webhooks.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Webhooks.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhooks.timeout: %w", err)
+		}
+		timeout = d
+	}
+
+	sinks := make([]service.WebhookSink, 0, len(cfg.Webhooks.Sinks))
+	for _, sink := range cfg.Webhooks.Sinks {
+		if sink.Name == "" || sink.URL == "" {
+			return nil, fmt.Errorf("webhooks.sinks: every sink needs a name and url")
+		}
+		switch sink.Format {
+		case "", service.WebhookJSON, service.WebhookSlack, service.WebhookDiscord, service.WebhookNtfy:
+		default:
+			return nil, fmt.Errorf("webhooks.sinks.%s: unknown format %q", sink.Name, sink.Format)
+		}
+		sinks = append(sinks, service.WebhookSink{
+			Name:   sink.Name,
+			URL:    sink.URL,
+			Format: sink.Format,
+			Secret: sink.Secret,
+			Events: sink.Events,
+		})
+	}
+
+	return service.NewWebhookService(sinks, webhook.NewSender(timeout), markdown.NewDeadLetterRepository(cfg.Storage.BasePath), service.WebhookConfig{
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		Backoff:     backoff,
+	}), nil
+}
+
+func newEmbeddingService(cfg *yaml.Config, trendSvc *service.TrendService) (*service.EmbeddingService, error) {
+	var timeout time.Duration
+	if cfg.Embedding.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Embedding.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedding.timeout: %w", err)
+		}
+		timeout = d
+	}
+
+	embedder, err := embedding.New(embedding.Config{
+		Provider:   cfg.Embedding.Provider,
+		Model:      cfg.Embedding.Model,
+		APIKey:     cfg.Embedding.APIKey,
+		BaseURL:    cfg.Embedding.BaseURL,
+		Dimensions: cfg.Embedding.Dimensions,
+		Timeout:    timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewEmbeddingService(embedder, markdown.NewEmbeddingRepository(cfg.Storage.BasePath), trendSvc), nil
+}
+
+func newUsageService(cfg *yaml.Config) (*service.UsageService, error) {
+	prices := make(map[string]service.ModelPrice, len(cfg.LLM.Usage.Prices))
+	for model, p := range cfg.LLM.Usage.Prices {
+		prices[model] = service.ModelPrice{Input: p.Input, Output: p.Output}
+	}
+
+	mode := cfg.LLM.Usage.BudgetMode
+	if mode == "" {
+		mode = service.BudgetRefuse
+	}
+
+	return service.NewUsageService(markdown.NewUsageRepository(cfg.Storage.BasePath),
+		service.WithPrices(prices),
+		service.WithDailyBudget(cfg.LLM.Usage.DailyBudget, mode),
+	)
+}
+
+func newLLMAgent(cfg yaml.LLMConfig, opts ...llm.AgentOption) (*llm.Agent, error) {
+	var timeout time.Duration
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm.timeout: %w", err)
+		}
+		timeout = d
+	}
+
+	retry := llm.RetryPolicy{MaxAttempts: cfg.Retry.MaxAttempts}
+	if cfg.Retry.BaseDelay != "" {
+		d, err := time.ParseDuration(cfg.Retry.BaseDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm.retry.base_delay: %w", err)
+		}
+		retry.BaseDelay = d
+	}
+	if cfg.Retry.MaxDelay != "" {
+		d, err := time.ParseDuration(cfg.Retry.MaxDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm.retry.max_delay: %w", err)
+		}
+		retry.MaxDelay = d
+	}
+
+	return agent.New(llm.Config{
+		Provider:       cfg.Provider,
+		Model:          cfg.Model,
+		APIKey:         cfg.APIKey,
+		BaseURL:        cfg.BaseURL,
+		Temperature:    cfg.Temperature,
+		MaxTokens:      cfg.MaxTokens,
+		Timeout:        timeout,
+		Fixtures:       cfg.Fixtures,
+		ResponseFormat: cfg.ResponseFormat,
+		Retry:          retry,
+	}, opts...)
 }
 
 func trendsHandler(trendSvc *service.TrendService) http.HandlerFunc {
@@ -123,65 +604,619 @@ func trendsHandler(trendSvc *service.TrendService) http.HandlerFunc {
 		}
 
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var dtos []interface{}
+		for _, t := range trends {
+			dtos = append(dtos, t.ToDTO())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"trends": dtos,
+			"total":  total,
+		})
+	}
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// healthHandler reports on the server's dependencies; it answers 503 while
+// a required one, such as storage, is unavailable.
+func healthHandler(healthSvc *service.HealthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		h := healthSvc.Check(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !h.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(h)
+	}
+}
+
+// instrument tags each request with an ID, taken from X-Request-ID or made
+// up, that is returned in the response and logged with everything done for
+// the request. It logs and records the count and latency of requests by the
+// mux pattern that served them; event streams are counted but not timed,
+// and probes are only logged at debug level.
+func instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		id := r.Header.Get("X-Request-ID")
+		if id == "" || len(id) > 128 {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		ctx := logging.WithRequestID(r.Context(), id)
+
+		started := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		mux.ServeHTTP(rec, r.WithContext(ctx))
+		d := time.Since(started)
+
+		metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(rec.status))
+		if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+			metrics.HTTPDuration.Observe(d.Seconds(), route, r.Method)
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case route == "/api/v1/health" || route == "/metrics":
+			level = slog.LevelDebug
+		}
+		slog.Log(ctx, level, "http request",
+			"method", r.Method, "path", r.URL.Path, "route", route, "status", rec.status, "duration", d)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach Flush and deadlines.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// eventsHandler streams domain events as server-sent events. Clients
+// resume with the Last-Event-ID header (or last_event_id, for browsers
+// reconnecting by hand) and may pick event types with types=a,b.
+func eventsHandler(stream *service.EventStream) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var types map[string]bool
+		if v := r.URL.Query().Get("types"); v != "" {
+			types = make(map[string]bool)
+			for _, t := range strings.Split(v, ",") {
+				types[strings.TrimSpace(t)] = true
+			}
+		}
+		lastID := r.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = r.URL.Query().Get("last_event_id")
+		}
+
+		// The stream outlives the server's write timeout.
+		rc := http.NewResponseController(w)
+		rc.SetWriteDeadline(time.Time{})
+
+		replay, events, cancel := stream.Listen(lastID)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "retry: 3000\n\n")
+
+		send := func(ev service.StreamEvent) error {
+			if types != nil && !types[ev.Type] {
+				return nil
+			}
+			_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+			return err
+		}
+		for _, ev := range replay {
+			if send(ev) != nil {
+				return
+			}
+		}
+		if rc.Flush() != nil {
+			return
+		}
+
+		ping := time.NewTicker(15 * time.Second)
+		defer ping.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ping.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			case ev, ok := <-events:
+				// A closed channel means the client fell behind or the
+				// server is stopping; it reconnects and catches up.
+				if !ok || send(ev) != nil {
+					return
+				}
+			}
+			if rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+// scoredTrendDTO is a trend returned by a similarity query.
+type scoredTrendDTO struct {
+	*entity.TrendDTO
+	Similarity float64 `json:"similarity"`
+}
+
+func scoredTrendDTOs(scored []service.ScoredTrend) []scoredTrendDTO {
+	dtos := make([]scoredTrendDTO, len(scored))
+	for i, s := range scored {
+		dtos[i] = scoredTrendDTO{TrendDTO: s.Trend.ToDTO(), Similarity: math.Round(s.Similarity*1000) / 1000}
+	}
+	return dtos
+}
+
+func queryLimit(r *http.Request, fallback int) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// trendSearchHandler matches q against titles and summaries, or with
+// semantic=true ranks trends by embedding similarity to q.
+func trendSearchHandler(trendSvc *service.TrendService, embeddingSvc *service.EmbeddingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query().Get("q")
+		if query == "" {
+			http.Error(w, "q is required", http.StatusBadRequest)
+			return
+		}
+		limit := queryLimit(r, 20)
+
+		if semantic, _ := strconv.ParseBool(r.URL.Query().Get("semantic")); semantic {
+			if embeddingSvc == nil {
+				http.Error(w, "Embeddings are not enabled", http.StatusServiceUnavailable)
+				return
+			}
+			scored, err := embeddingSvc.Search(r.Context(), query, limit)
+			if err != nil {
+				writeAgentError(w, err)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"trends": scoredTrendDTOs(scored),
+				"total":  len(scored),
+			})
+			return
+		}
+
+		trends, total, err := trendSvc.Search(r.Context(), query, service.SearchOptions{Limit: limit})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		dtos := make([]*entity.TrendDTO, len(trends))
+		for i, t := range trends {
+			dtos[i] = t.ToDTO()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"trends": dtos,
+			"total":  total,
+		})
+	}
+}
+
+func trendDetailHandler(trendSvc *service.TrendService, embeddingSvc *service.EmbeddingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[len("/api/v1/trends/"):]
+
+		if strings.HasSuffix(id, "/related") {
+			trendID := strings.TrimSuffix(id, "/related")
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if embeddingSvc == nil {
+				http.Error(w, "Embeddings are not enabled", http.StatusServiceUnavailable)
+				return
+			}
+			related, err := embeddingSvc.Related(r.Context(), trendID, queryLimit(r, 10))
+			if err != nil {
+				writeAgentError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"trend_id": trendID,
+				"related":  scoredTrendDTOs(related),
+			})
+			return
+		}
+
+		if strings.HasSuffix(id, "/star") {
+			trendID := id[:len(id)-5]
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if err := trendSvc.Star(r.Context(), trendID); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"status": "starred"})
+			return
+		}
+
+		if strings.HasSuffix(id, "/unstar") {
+			trendID := id[:len(id)-7]
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if err := trendSvc.Unstar(r.Context(), trendID); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"status": "unstarred"})
+			return
+		}
+
+		trend, err := trendSvc.Get(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(trend.ToDTO())
+	}
+}
+
+// clustersHandler lists the storylines of a day, today by default,
+// clustering the day on first request or with refresh=true.
+func clustersHandler(clusterSvc *service.ClusterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		date := r.URL.Query().Get("date")
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			http.Error(w, "date must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh"))
+
+		clusters, err := clusterSvc.Clusters(r.Context(), date, refresh)
+		if err != nil {
+			writeAgentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"date":     date,
+			"clusters": clusters,
+			"total":    len(clusters),
+		})
+	}
+}
+
+// digestsHandler generates the digest of a period, today by default, and
+// emails it with "send": true.
+func digestsHandler(digestSvc *service.DigestService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req service.DigestRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request", http.StatusBadRequest)
+				return
+			}
+		}
+		if req.Profile == "" {
+			req.Profile = activeProfile
+		}
+
+		d, err := digestSvc.Generate(r.Context(), req)
+		if err != nil {
+			writeAgentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(d)
+	}
+}
+
+// feedsHandler serves /api/v1/feeds/{profile}.atom, .rss and .json,
+// filtered by the starred, source, tag and min_score query parameters.
+func feedsHandler(feedSvc *service.FeedService) http.HandlerFunc {
+	formats := map[string]struct {
+		contentType string
+		render      func(*feed.Feed) ([]byte, error)
+	}{
+		".atom": {feed.AtomContentType, feed.Atom},
+		".rss":  {feed.RSSContentType, feed.RSS},
+		".json": {feed.JSONContentType, feed.JSON},
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/api/v1/feeds/")
+		ext := filepath.Ext(name)
+		profile := strings.TrimSuffix(name, ext)
+		format, ok := formats[ext]
+		if !ok || profile == "" || strings.ContainsAny(profile, `/\.`) {
+			http.NotFound(w, r)
+			return
+		}
+
+		q := r.URL.Query()
+		req := service.FeedRequest{
+			Profile: profile,
+			Starred: q.Get("starred") == "true",
+			Source:  q.Get("source"),
+			Tag:     q.Get("tag"),
+			Limit:   queryLimit(r, service.DefaultFeedLimit),
+		}
+		if v := q.Get("min_score"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "Invalid request", http.StatusBadRequest)
+				return
+			}
+			req.MinScore = n
+		}
+
+		f, err := feedSvc.Feed(r.Context(), req)
+		if err != nil {
+			writeAgentError(w, err)
+			return
+		}
+
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		}
+		f.Link = scheme + "://" + r.Host + "/"
+		f.Self = scheme + "://" + r.Host + r.URL.RequestURI()
+
+		body, err := format.render(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", format.contentType)
+		w.Write(body)
+	}
+}
+
+// webhookTestHandler sends a sample notification to the sink named in the
+// body, or to every sink, and reports how each answered.
+func webhookTestHandler(webhookSvc *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req struct {
+			Sink string `json:"sink"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request", http.StatusBadRequest)
+				return
+			}
+		}
+
+		results, err := webhookSvc.Test(r.Context(), req.Sink)
+		if err != nil {
+			writeAgentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": results,
+			"total":   len(results),
+		})
+	}
+}
+
+func deadLettersHandler(webhookSvc *service.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		letters, err := webhookSvc.DeadLetters(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"dead_letters": letters,
+			"total":        len(letters),
+		})
+	}
+}
+
+// alertsHandler lists a profile's watch rule alerts, newest first, only
+// unseen ones with unseen=true.
+func alertsHandler(watchSvc *service.WatchService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		profile := r.URL.Query().Get("profile")
+		if profile == "" {
+			profile = activeProfile
+		}
+		unseenOnly, _ := strconv.ParseBool(r.URL.Query().Get("unseen"))
+
+		alerts, err := watchSvc.Alerts(r.Context(), profile, unseenOnly)
+		if err != nil {
+			writeAgentError(w, err)
 			return
 		}
 
-		var dtos []interface{}
-		for _, t := range trends {
-			dtos = append(dtos, t.ToDTO())
+		unseen := 0
+		for _, a := range alerts {
+			if !a.Seen {
+				unseen++
+			}
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"trends": dtos,
-			"total":  total,
+			"alerts": alerts,
+			"total":  len(alerts),
+			"unseen": unseen,
 		})
 	}
 }
 
-func trendDetailHandler(trendSvc *service.TrendService) http.HandlerFunc {
+// alertsSeenHandler marks the alerts in the body's ids as seen, or all of
+// them if ids is empty.
+func alertsSeenHandler(watchSvc *service.WatchService, activeProfile string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := r.URL.Path[len("/api/v1/trends/"):]
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-		if strings.HasSuffix(id, "/star") {
-			trendID := id[:len(id)-5]
-			if r.Method != http.MethodPost {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-				return
-			}
-			if err := trendSvc.Star(r.Context(), trendID); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+		profile := r.URL.Query().Get("profile")
+		if profile == "" {
+			profile = activeProfile
+		}
+
+		var req struct {
+			IDs []string `json:"ids"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request", http.StatusBadRequest)
 				return
 			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{"status": "starred"})
+		}
+
+		marked, err := watchSvc.MarkSeen(r.Context(), profile, req.IDs)
+		if err != nil {
+			writeAgentError(w, err)
 			return
 		}
 
-		if strings.HasSuffix(id, "/unstar") {
-			trendID := id[:len(id)-7]
-			if r.Method != http.MethodPost {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-				return
-			}
-			if err := trendSvc.Unstar(r.Context(), trendID); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int{"marked": marked})
+	}
+}
+
+// keywordsHandler lists the terms rising between from and to, the last week
+// by default.
+func keywordsHandler(keywordSvc *service.KeywordService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+		for _, date := range []string{from, to} {
+			if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+				http.Error(w, "from and to must be YYYY-MM-DD", http.StatusBadRequest)
 				return
 			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{"status": "unstarred"})
+		}
+		if from != "" && to != "" && from > to {
+			http.Error(w, "from must not be after to", http.StatusBadRequest)
 			return
 		}
 
-		trend, err := trendSvc.Get(r.Context(), id)
+		rising, err := keywordSvc.Rising(r.Context(), from, to, queryLimit(r, 50))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeAgentError(w, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(trend.ToDTO())
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"from":     rising.From,
+			"to":       rising.To,
+			"keywords": rising.Keywords,
+			"total":    len(rising.Keywords),
+		})
 	}
 }
 
@@ -192,32 +1227,162 @@ func collectHandler(collectorSvc *service.CollectorService, configPath, activePr
 			return
 		}
 
-		sourceLoader := yaml.NewSourceLoader(configPath + "/sources")
-		sources, err := sourceLoader.LoadAll(r.Context())
+		result, err := collectAll(r.Context(), collectorSvc, configPath, activeProfile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":      "completed",
+			"items_count": len(result.Trends),
+			"errors":      result.Errors,
+		})
+	}
+}
+
+// collectAll collects from every enabled source.
+func collectAll(ctx context.Context, collectorSvc *service.CollectorService, configPath, profile string) (*entity.CollectionResult, error) {
+	sourceLoader := yaml.NewSourceLoader(configPath + "/sources")
+	sources, err := sourceLoader.LoadAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sources: %w", err)
+	}
+
+	var sourceIDs []string
+	for _, s := range sources {
+		if s.Enabled() {
+			sourceIDs = append(sourceIDs, s.ID())
+		}
+	}
+
+	result, err := collectorSvc.Collect(ctx, profile, sourceIDs, sources)
+	if err != nil {
+		return nil, fmt.Errorf("collection failed: %w", err)
+	}
+	return result, nil
+}
+
+// exportHandler streams the trends of a profile as JSON Lines or CSV.
+func exportHandler(exchangeSvc *service.ExchangeService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		format := q.Get("format")
+		if format == "" {
+			format = exchange.JSONL
+		}
+		req := service.ExportRequest{Profile: q.Get("profile"), From: q.Get("from"), To: q.Get("to")}
+		if req.Profile == "" {
+			req.Profile = activeProfile
+		}
+
+		enc, err := exchange.NewEncoder(format, w)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to load sources: %v", err), http.StatusInternalServerError)
+			writeAgentError(w, err)
 			return
 		}
 
-		var sourceIDs []string
-		for _, s := range sources {
-			if s.Enabled() {
-				sourceIDs = append(sourceIDs, s.ID())
+		started := false
+		err = exchangeSvc.Export(r.Context(), req, func(t *entity.Trend) error {
+			if !started {
+				started = true
+				w.Header().Set("Content-Type", exchange.ContentType(format))
+				w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="trends-%s.%s"`, req.Profile, format))
 			}
+			return enc.Encode(t.ToDTO())
+		})
+		if err == nil {
+			err = enc.Flush()
+		}
+		switch {
+		case err != nil && !started:
+			writeAgentError(w, err)
+		case err != nil:
+			// The status is sent; all that is left is to cut the export short.
+			slog.ErrorContext(r.Context(), "export failed", "error", err)
+		case !started:
+			w.Header().Set("Content-Type", exchange.ContentType(format))
+		}
+	}
+}
+
+// importHandler merges trends from a JSON Lines or CSV body by ID.
+func importHandler(exchangeSvc *service.ExchangeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = exchange.JSONL
+		}
+
+		var trends []*entity.Trend
+		err := exchange.Decode(format, r.Body, func(dto *entity.TrendDTO) error {
+			trends = append(trends, entity.TrendFromDTO(dto))
+			return nil
+		})
+		if err != nil {
+			writeAgentError(w, err)
+			return
 		}
 
-		result, err := collectorSvc.Collect(r.Context(), activeProfile, sourceIDs, sources)
+		result, err := exchangeSvc.Import(r.Context(), trends)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Collection failed: %v", err), http.StatusInternalServerError)
+			writeAgentError(w, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":      "completed",
-			"items_count": len(result.Trends),
-			"errors":      result.Errors,
-		})
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// opmlHandler exports a profile's feed sources as OPML on GET and adds the
+// feeds of an OPML body on POST.
+func opmlHandler(exchangeSvc *service.ExchangeService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profile := r.URL.Query().Get("profile")
+		if profile == "" {
+			profile = activeProfile
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			subs, err := exchangeSvc.Subscriptions(r.Context(), profile)
+			if err != nil {
+				writeAgentError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", exchange.OPMLContentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.opml"`, profile))
+			exchange.WriteOPML(w, profile+" feeds", subs)
+
+		case http.MethodPost:
+			subs, err := exchange.ReadOPML(r.Body)
+			if err != nil {
+				writeAgentError(w, err)
+				return
+			}
+			result, err := exchangeSvc.Subscribe(r.Context(), profile, subs)
+			if err != nil {
+				writeAgentError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(result)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
 	}
 }
 
@@ -273,6 +1438,7 @@ func agentSummarizeHandler(agentSvc *service.AgentService) http.HandlerFunc {
 		var req struct {
 			TrendID string `json:"trend_id"`
 			Content string `json:"content"`
+			Refresh bool   `json:"refresh"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -280,20 +1446,32 @@ func agentSummarizeHandler(agentSvc *service.AgentService) http.HandlerFunc {
 			return
 		}
 
-		var summary string
-		var err error
-
 		if req.TrendID != "" {
-			summary, err = agentSvc.Summarize(r.Context(), req.TrendID)
-		} else if req.Content != "" {
-			summary, err = agentSvc.SummarizeContent(r.Context(), req.Content)
-		} else {
+			rec, err := agentSvc.Summarize(r.Context(), req.TrendID, req.Refresh)
+			if err != nil {
+				writeAgentError(w, err)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"summary":        rec.Text,
+				"provider":       rec.Provider,
+				"model":          rec.Model,
+				"prompt_version": rec.PromptVersion,
+				"created_at":     rec.CreatedAt,
+			})
+			return
+		}
+
+		if req.Content == "" {
 			http.Error(w, "trend_id or content required", http.StatusBadRequest)
 			return
 		}
 
+		summary, err := agentSvc.SummarizeContent(r.Context(), req.Content)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeAgentError(w, err)
 			return
 		}
 
@@ -302,7 +1480,68 @@ func agentSummarizeHandler(agentSvc *service.AgentService) http.HandlerFunc {
 	}
 }
 
-func agentSuggestHandler(agentSvc *service.AgentService) http.HandlerFunc {
+// writeAgentError answers with the status for an agent service error and,
+// for provider rate limits, passes on the provider's Retry-After.
+func writeAgentError(w http.ResponseWriter, err error) {
+	var apiErr *llm.APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(apiErr.RetryAfter.Seconds())))
+	}
+	http.Error(w, err.Error(), agentErrorStatus(err))
+}
+
+// agentErrorStatus maps agent service errors to HTTP status codes. A bad
+// provider API key is the server's problem, hence 502 rather than 401.
+func agentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrProfileNotFound), errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrTrendNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrNoDraftSources), errors.Is(err, domain.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrBudgetExceeded), errors.Is(err, domain.ErrLLMRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, domain.ErrLLMContextLength):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrLLMAuth), errors.Is(err, domain.ErrLLMInvalidOutput):
+		return http.StatusBadGateway
+	case errors.Is(err, domain.ErrLLMUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func agentUsageHandler(usageSvc *service.UsageService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		report, err := usageSvc.Report(r.Context(), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+func agentCacheHandler(llmCache *cache.Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(llmCache.Stats())
+	}
+}
+
+func agentSuggestHandler(agentSvc *service.AgentService, activeProfile string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -311,18 +1550,143 @@ func agentSuggestHandler(agentSvc *service.AgentService) http.HandlerFunc {
 
 		profile := r.URL.Query().Get("profile")
 		if profile == "" {
-			profile = "tech"
+			profile = activeProfile
 		}
 
-		suggestions, err := agentSvc.SuggestTopics(r.Context(), profile)
+		result, err := agentSvc.SuggestTopics(r.Context(), profile)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeAgentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"suggestions": suggestionDTOs(result.Suggestions),
+			"inputs":      result.Inputs,
+		})
+	}
+}
+
+// agentDraftHandler writes a blog post from a suggestion, a list of trends
+// or, with an empty body, the starred trends.
+func agentDraftHandler(agentSvc *service.AgentService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req struct {
+			Profile      string   `json:"profile"`
+			SuggestionID string   `json:"suggestion_id"`
+			TrendIDs     []string `json:"trend_ids"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request", http.StatusBadRequest)
+				return
+			}
+		}
+		if req.Profile == "" {
+			req.Profile = r.URL.Query().Get("profile")
+		}
+		if req.Profile == "" {
+			req.Profile = activeProfile
+		}
+
+		draft, err := agentSvc.Draft(r.Context(), service.DraftRequest{
+			Profile:      req.Profile,
+			SuggestionID: req.SuggestionID,
+			TrendIDs:     req.TrendIDs,
+		})
+		if err != nil {
+			writeAgentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(draft)
+	}
+}
+
+func suggestionDTOs(suggestions []*entity.Suggestion) []*entity.SuggestionDTO {
+	dtos := make([]*entity.SuggestionDTO, len(suggestions))
+	for i, s := range suggestions {
+		dtos[i] = s.ToDTO()
+	}
+	return dtos
+}
+
+func suggestionsHandler(suggestionSvc *service.SuggestionService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		profile := r.URL.Query().Get("profile")
+		if profile == "" {
+			profile = activeProfile
+		}
+
+		var status entity.SuggestionStatus
+		if s := r.URL.Query().Get("status"); s != "" {
+			parsed, err := entity.ParseSuggestionStatus(s)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			status = parsed
+		}
+
+		suggestions, err := suggestionSvc.List(r.Context(), profile, status)
+		if err != nil {
+			writeAgentError(w, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"suggestions": suggestions,
+			"suggestions": suggestionDTOs(suggestions),
+			"total":       len(suggestions),
 		})
 	}
 }
+
+func suggestionDetailHandler(suggestionSvc *service.SuggestionService, activeProfile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/agent/suggestions/")
+		profile := r.URL.Query().Get("profile")
+		if profile == "" {
+			profile = activeProfile
+		}
+
+		var req struct {
+			Status string `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request", http.StatusBadRequest)
+			return
+		}
+		status, err := entity.ParseSuggestionStatus(req.Status)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		suggestion, err := suggestionSvc.SetStatus(r.Context(), profile, id, status)
+		if err != nil {
+			writeAgentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(suggestion.ToDTO())
+	}
+}
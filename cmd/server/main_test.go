@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func newTestAgentService(t *testing.T, provider *fake.Provider) *service.AgentService {
+	t.Helper()
+
+	repo := markdown.NewTrendRepositoryAdapter(t.TempDir())
+	trend := entity.NewTrend("hn-1", "Go 1.26 released", "https://go.dev/blog/go1.26")
+	trend.SetSource("Hacker News")
+	trend.SetScore(420)
+	if err := repo.SaveBatch(context.Background(), []*entity.Trend{trend}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+
+	return service.NewAgentService(
+		llm.NewAgent(provider),
+		service.NewTrendService(repo),
+		yaml.NewProfileLoader("../../config/profiles"),
+		"tech",
+	)
+}
+
+func TestAgentSuggestHandler(t *testing.T) {
+	provider := fake.New()
+	handler := agentSuggestHandler(newTestAgentService(t, provider), "tech")
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/agent/suggest", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		Suggestions []service.TopicSuggestion `json:"suggestions"`
+		Inputs      []service.SelectedTrend   `json:"inputs"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Suggestions) != 1 || !strings.Contains(body.Suggestions[0].Title, "Go 1.26 released") {
+		t.Errorf("suggestions = %#v", body.Suggestions)
+	}
+	if len(body.Inputs) != 1 || body.Inputs[0].ID != "hn-1" {
+		t.Errorf("inputs = %#v, want the trend shown to the model", body.Inputs)
+	}
+	if got := body.Suggestions[0].Topics; len(got) != 1 || got[0] != "hn-1" {
+		t.Errorf("topics = %v, want the source trend ID", got)
+	}
+
+	calls := provider.Calls()
+	if len(calls) != 1 || !strings.Contains(calls[0].Messages[1].Content, "[hn-1] Go 1.26 released (Hacker News, 420 points)") {
+		t.Errorf("prompt was not rendered from the profile template: %#v", calls)
+	}
+}
+
+func TestAgentSuggestHandlerUnknownProfile(t *testing.T) {
+	handler := agentSuggestHandler(newTestAgentService(t, fake.New()), "tech")
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/agent/suggest?profile=nope", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestSuggestionsHandlerRejectsPathLikeProfiles(t *testing.T) {
+	suggestions := service.NewSuggestionService(markdown.NewSuggestionRepository(t.TempDir()),
+		yaml.NewProfileLoader("../../config/profiles"), 0)
+	handler := suggestionsHandler(suggestions, "tech")
+
+	for _, profile := range []string{"nope", "..%2Fconfig", "..%5Ctech"} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/suggestions?profile="+profile, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("profile %s: status = %d, want 404", profile, rec.Code)
+		}
+	}
+}
+
+func TestAgentSummarizeHandler(t *testing.T) {
+	provider := fake.New().On("Go 1.26 released", "Go 1.26 is out.")
+	handler := agentSummarizeHandler(newTestAgentService(t, provider))
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{name: "by trend id", body: `{"trend_id": "hn-1"}`, status: http.StatusOK, want: "Go 1.26 is out."},
+		{name: "missing input", body: `{}`, status: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/agent/summarize", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.status, rec.Body)
+			}
+			if tt.want == "" {
+				return
+			}
+
+			var body map[string]string
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body["summary"] != tt.want {
+				t.Errorf("summary = %q, want %q", body["summary"], tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentSummarizeHandlerStoresSummary(t *testing.T) {
+	provider := fake.New().On("Go 1.26 released", "Go 1.26 is out.")
+	handler := agentSummarizeHandler(newTestAgentService(t, provider))
+
+	summarize := func(body string) map[string]string {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/agent/summarize", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s)", rec.Code, rec.Body)
+		}
+		var out map[string]string
+		json.NewDecoder(rec.Body).Decode(&out)
+		return out
+	}
+
+	first := summarize(`{"trend_id": "hn-1"}`)
+	if first["provider"] != "fake" || first["prompt_version"] == "" {
+		t.Errorf("missing provenance: %v", first)
+	}
+
+	if again := summarize(`{"trend_id": "hn-1"}`); again["created_at"] != first["created_at"] || len(provider.Calls()) != 1 {
+		t.Errorf("stored summary was not reused: %v, %d calls", again, len(provider.Calls()))
+	}
+
+	summarize(`{"trend_id": "hn-1", "refresh": true}`)
+	if len(provider.Calls()) != 2 {
+		t.Errorf("refresh did not regenerate the summary: %d calls", len(provider.Calls()))
+	}
+}
+
+func TestAgentSummarizeHandlerRateLimited(t *testing.T) {
+	provider := fake.New()
+	provider.Rules(fake.Rule{Match: "Go 1.26", Error: "rate limit reached", Status: http.StatusTooManyRequests, RetryAfter: 30})
+	handler := agentSummarizeHandler(newTestAgentService(t, provider))
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/agent/summarize", strings.NewReader(`{"trend_id": "hn-1"}`)))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+}
@@ -0,0 +1,214 @@
+// Command trendsctl exports and imports trends and feed subscriptions
+// directly against the configured storage, without a running server.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/exchange"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+const usage = `Usage: trendsctl <command> [flags]
+
+Commands:
+  export       Write trends as JSON Lines or CSV
+  import       Merge trends from JSON Lines or CSV by ID
+  opml-export  Write the profile's feed sources as OPML
+  opml-import  Add the feeds of an OPML file as sources
+
+The config directory is $CONFIG_PATH, ./config by default. Run
+"trendsctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(ctx context.Context, svc *service.ExchangeService, profile string, args []string) error{
+		"export":      exportCmd,
+		"import":      importCmd,
+		"opml-export": opmlExportCmd,
+		"opml-import": opmlImportCmd,
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "./config"
+	}
+	cfg, err := yaml.NewConfigLoader(configPath).Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	svc := service.NewExchangeService(
+		markdown.NewTrendRepositoryAdapter(cfg.Storage.BasePath),
+		yaml.NewProfileLoader(filepath.Join(configPath, "profiles")),
+		yaml.NewSourceLoader(filepath.Join(configPath, "sources")),
+	)
+	if err := run(context.Background(), svc, cfg.ActiveProfile, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func exportCmd(ctx context.Context, svc *service.ExchangeService, profile string, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", exchange.JSONL, "jsonl or csv")
+	from := fs.String("from", "", "first day collected, YYYY-MM-DD")
+	to := fs.String("to", "", "last day collected, YYYY-MM-DD")
+	fs.StringVar(&profile, "profile", profile, "profile")
+	out := fs.String("o", "-", "output file, - for stdout")
+	fs.Parse(args)
+
+	w, closeOutput, err := output(*out)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	buf := bufio.NewWriter(w)
+	enc, err := exchange.NewEncoder(*format, buf)
+	if err != nil {
+		return err
+	}
+	n := 0
+	err = svc.Export(ctx, service.ExportRequest{Profile: profile, From: *from, To: *to}, func(t *entity.Trend) error {
+		n++
+		return enc.Encode(t.ToDTO())
+	})
+	if err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d trends\n", n)
+	return nil
+}
+
+func importCmd(ctx context.Context, svc *service.ExchangeService, profile string, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "jsonl or csv, by default from the file extension")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("import needs one file, - for stdin")
+	}
+
+	name := fs.Arg(0)
+	if *format == "" {
+		*format = exchange.JSONL
+		if strings.EqualFold(filepath.Ext(name), ".csv") {
+			*format = exchange.CSV
+		}
+	}
+	r, closeInput, err := input(name)
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	var trends []*entity.Trend
+	err = exchange.Decode(*format, r, func(dto *entity.TrendDTO) error {
+		trends = append(trends, entity.TrendFromDTO(dto))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := svc.Import(ctx, trends)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d trends: %d added, %d updated\n", len(trends), result.Added, result.Updated)
+	return nil
+}
+
+func opmlExportCmd(ctx context.Context, svc *service.ExchangeService, profile string, args []string) error {
+	fs := flag.NewFlagSet("opml-export", flag.ExitOnError)
+	fs.StringVar(&profile, "profile", profile, "profile")
+	out := fs.String("o", "-", "output file, - for stdout")
+	fs.Parse(args)
+
+	subs, err := svc.Subscriptions(ctx, profile)
+	if err != nil {
+		return err
+	}
+	w, closeOutput, err := output(*out)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+	return exchange.WriteOPML(w, profile+" feeds", subs)
+}
+
+func opmlImportCmd(ctx context.Context, svc *service.ExchangeService, profile string, args []string) error {
+	fs := flag.NewFlagSet("opml-import", flag.ExitOnError)
+	fs.StringVar(&profile, "profile", profile, "profile")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("opml-import needs one file, - for stdin")
+	}
+
+	r, closeInput, err := input(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	subs, err := exchange.ReadOPML(r)
+	if err != nil {
+		return err
+	}
+	result, err := svc.Subscribe(ctx, profile, subs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Added %d feeds (disabled) to %s, skipped %d already present\n",
+		result.Added, yaml.ImportedSourcesFile, result.Skipped)
+	return nil
+}
+
+func input(name string) (io.Reader, func(), error) {
+	if name == "-" {
+		return os.Stdin, func() {}, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+func output(name string) (io.Writer, func() error, error) {
+	if name == "-" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
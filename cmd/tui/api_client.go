@@ -1,11 +1,15 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -24,17 +28,25 @@ func NewAPIClient(baseURL string) *APIClient {
 }
 
 type TrendDTO struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	URL         string    `json:"url"`
-	Summary     string    `json:"summary"`
-	Score       int       `json:"score"`
-	Author      string    `json:"author"`
-	Source      string    `json:"source"`
-	SourceID    string    `json:"source_id"`
-	Timestamp   time.Time `json:"timestamp"`
-	CollectedAt time.Time `json:"collected_at"`
-	Starred     bool      `json:"starred"`
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	URL         string       `json:"url"`
+	Summary     string       `json:"summary"`
+	Score       int          `json:"score"`
+	Author      string       `json:"author"`
+	Source      string       `json:"source"`
+	SourceID    string       `json:"source_id"`
+	Timestamp   time.Time    `json:"timestamp"`
+	CollectedAt time.Time    `json:"collected_at"`
+	Starred     bool         `json:"starred"`
+	Summaries   []SummaryDTO `json:"summaries"`
+}
+
+type SummaryDTO struct {
+	Text      string    `json:"text"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type SourceDTO struct {
@@ -60,6 +72,32 @@ type SourcesResponse struct {
 	Sources []SourceDTO `json:"sources"`
 }
 
+type ClusterDTO struct {
+	ID       string   `json:"id"`
+	Date     string   `json:"date"`
+	Name     string   `json:"name"`
+	TrendIDs []string `json:"trend_ids"`
+}
+
+type ClustersResponse struct {
+	Date     string       `json:"date"`
+	Clusters []ClusterDTO `json:"clusters"`
+}
+
+type AlertDTO struct {
+	ID      string `json:"id"`
+	Rule    string `json:"rule"`
+	TrendID string `json:"trend_id"`
+	Title   string `json:"title"`
+	Seen    bool   `json:"seen"`
+}
+
+type AlertsResponse struct {
+	Alerts []AlertDTO `json:"alerts"`
+	Total  int        `json:"total"`
+	Unseen int        `json:"unseen"`
+}
+
 type CollectResponse struct {
 	Status     string   `json:"status"`
 	ItemsCount int      `json:"items_count"`
@@ -106,6 +144,58 @@ func (c *APIClient) GetSources() (*SourcesResponse, error) {
 	return &sources, nil
 }
 
+func (c *APIClient) GetClusters(date string) (*ClustersResponse, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/v1/clusters?date=" + url.QueryEscape(date))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to load clusters: HTTP %d", resp.StatusCode)
+	}
+
+	var clusters ClustersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&clusters); err != nil {
+		return nil, err
+	}
+
+	return &clusters, nil
+}
+
+func (c *APIClient) GetUnseenAlerts() (*AlertsResponse, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/v1/alerts?unseen=true")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to load alerts: HTTP %d", resp.StatusCode)
+	}
+
+	var alerts AlertsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return nil, err
+	}
+
+	return &alerts, nil
+}
+
+func (c *APIClient) MarkAlertsSeen() error {
+	resp, err := c.httpClient.Post(c.baseURL+"/api/v1/alerts/seen", "application/json", bytes.NewReader([]byte{}))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("failed to mark alerts seen: HTTP %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 func (c *APIClient) Collect() (*CollectResponse, error) {
 	resp, err := c.httpClient.Post(c.baseURL+"/api/v1/collect", "application/json", bytes.NewReader([]byte{}))
 	if err != nil {
@@ -154,3 +244,84 @@ func (c *APIClient) HealthCheck() error {
 
 	return nil
 }
+
+// ServerEvent is a domain event received from /api/v1/events.
+type ServerEvent struct {
+	ID   string
+	Type string
+	Data json.RawMessage
+}
+
+// WatchEvents sends server events to out until ctx is done, reconnecting
+// with Last-Event-ID whenever the stream drops.
+func (c *APIClient) WatchEvents(ctx context.Context, out chan<- ServerEvent) {
+	lastID := ""
+	for {
+		lastID, _ = c.streamEvents(ctx, lastID, out)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
+
+func (c *APIClient) streamEvents(ctx context.Context, lastID string, out chan<- ServerEvent) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/events", nil)
+	if err != nil {
+		return lastID, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+
+	// The stream stays open, so the client's request timeout cannot apply.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return lastID, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return lastID, fmt.Errorf("failed to stream events: HTTP %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	var ev ServerEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if ev.Type != "" {
+				ev.Data = json.RawMessage(strings.Join(data, "\n"))
+				if ev.ID != "" {
+					lastID = ev.ID
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return lastID, ctx.Err()
+				}
+			}
+			ev, data = ServerEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			ev.ID = value
+		case "event":
+			ev.Type = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	return lastID, scanner.Err()
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -24,6 +27,11 @@ type model struct {
 	loading     bool
 	collecting  bool
 	lastError   string
+	// trends is the list as loaded; grouped shows it ordered by storyline.
+	trends  []components.TrendItem
+	grouped bool
+	// events receives server events for live updates.
+	events chan ServerEvent
 }
 
 type trendsLoadedMsg struct {
@@ -42,11 +50,24 @@ type collectCompleteMsg struct {
 	err    error
 }
 
+type clustersLoadedMsg struct {
+	clusters []ClusterDTO
+	err      error
+}
+
+// alertsLoadedMsg carries the number of unseen watch alerts.
+type alertsLoadedMsg struct {
+	unseen int
+	err    error
+}
+
 type starCompleteMsg struct {
 	trendID string
 	err     error
 }
 
+type serverEventMsg ServerEvent
+
 func initialModel() model {
 	apiURL := os.Getenv("API_URL")
 	if apiURL == "" {
@@ -62,6 +83,7 @@ func initialModel() model {
 		detailView:  components.NewDetailView(),
 		focusedPane: 0,
 		loading:     true,
+		events:      make(chan ServerEvent, 16),
 	}
 }
 
@@ -69,9 +91,17 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		loadTrends(m.apiClient),
 		loadSources(m.apiClient),
+		loadAlerts(m.apiClient),
+		waitForEvent(m.events),
 	)
 }
 
+func waitForEvent(events <-chan ServerEvent) tea.Cmd {
+	return func() tea.Msg {
+		return serverEventMsg(<-events)
+	}
+}
+
 func loadTrends(api *APIClient) tea.Cmd {
 	return func() tea.Msg {
 		resp, err := api.GetTrends()
@@ -92,6 +122,13 @@ func loadTrends(api *APIClient) tea.Cmd {
 				Author:    t.Author,
 				Timestamp: t.Timestamp,
 				Starred:   t.Starred,
+				// CollectedAt picks the day whose clusters the trend is in.
+				CollectedAt: t.CollectedAt,
+			}
+			if n := len(t.Summaries); n > 0 {
+				latest := t.Summaries[n-1]
+				trends[i].LLMSummary = latest.Text
+				trends[i].LLMSummaryBy = fmt.Sprintf("%s/%s, %s", latest.Provider, latest.Model, latest.CreatedAt.Local().Format("2006-01-02 15:04"))
 			}
 		}
 
@@ -120,6 +157,95 @@ func loadSources(api *APIClient) tea.Cmd {
 	}
 }
 
+func loadAlerts(api *APIClient) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := api.GetUnseenAlerts()
+		if err != nil {
+			return alertsLoadedMsg{err: err}
+		}
+		return alertsLoadedMsg{unseen: resp.Unseen}
+	}
+}
+
+func markAlertsSeen(api *APIClient) tea.Cmd {
+	return func() tea.Msg {
+		if err := api.MarkAlertsSeen(); err != nil {
+			return alertsLoadedMsg{err: err}
+		}
+		return alertsLoadedMsg{}
+	}
+}
+
+// loadClusters fetches the clusters of the most recent days among trends.
+func loadClusters(api *APIClient, trends []components.TrendItem) tea.Cmd {
+	seen := make(map[string]bool)
+	var dates []string
+	for _, t := range trends {
+		date := t.CollectedAt.Format("2006-01-02")
+		if !seen[date] {
+			seen[date] = true
+			dates = append(dates, date)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+	if len(dates) > 7 {
+		dates = dates[:7]
+	}
+
+	return func() tea.Msg {
+		var clusters []ClusterDTO
+		for _, date := range dates {
+			resp, err := api.GetClusters(date)
+			if err != nil {
+				return clustersLoadedMsg{err: err}
+			}
+			clusters = append(clusters, resp.Clusters...)
+		}
+		return clustersLoadedMsg{clusters: clusters}
+	}
+}
+
+// groupByCluster orders trends by storyline, largest first, with each
+// trend labelled by its storyline's most recent name. A storyline spanning
+// several days is one group.
+func groupByCluster(trends []components.TrendItem, clusters []ClusterDTO) []components.TrendItem {
+	storyline := make(map[string]string)
+	names := make(map[string]string)
+	for _, c := range clusters {
+		for _, id := range c.TrendIDs {
+			if _, ok := storyline[id]; !ok {
+				storyline[id] = c.ID
+			}
+		}
+		if _, ok := names[c.ID]; !ok || len(c.TrendIDs) > 1 {
+			names[c.ID] = c.Name
+		}
+	}
+
+	size := make(map[string]int)
+	for _, t := range trends {
+		size[storyline[t.ID]]++
+	}
+
+	grouped := make([]components.TrendItem, len(trends))
+	copy(grouped, trends)
+	for i := range grouped {
+		grouped[i].Cluster = names[storyline[grouped[i].ID]]
+	}
+
+	sort.SliceStable(grouped, func(i, j int) bool {
+		a, b := storyline[grouped[i].ID], storyline[grouped[j].ID]
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		if size[a] != size[b] {
+			return size[a] > size[b]
+		}
+		return a < b
+	})
+	return grouped
+}
+
 func collectTrends(api *APIClient) tea.Cmd {
 	return func() tea.Msg {
 		result, err := api.Collect()
@@ -188,10 +314,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if t := m.trendsList.SelectedTrend(); t != nil {
 				m.detailView.SetTrend(t)
 			}
+		case "g":
+			m.grouped = !m.grouped
+			if m.grouped {
+				m.header.SetStatus("Grouping by storyline...")
+				cmds = append(cmds, loadClusters(m.apiClient, m.trends))
+			} else {
+				m.trendsList.SetTitle("TRENDS")
+				m.trendsList.SetTrends(m.trends)
+			}
+		case "a":
+			cmds = append(cmds, markAlertsSeen(m.apiClient))
 		case "r":
 			m.loading = true
 			m.header.SetStatus("Refreshing...")
-			cmds = append(cmds, loadTrends(m.apiClient))
+			cmds = append(cmds, loadTrends(m.apiClient), loadAlerts(m.apiClient))
 		}
 
 	case tea.WindowSizeMsg:
@@ -217,7 +354,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.lastError = msg.err.Error()
 			m.header.SetStatus("Error loading trends")
 		} else {
+			m.trends = msg.trends
 			m.trendsList.SetTrends(msg.trends)
+			if m.grouped {
+				cmds = append(cmds, loadClusters(m.apiClient, msg.trends))
+			}
 			m.footer.SetStats("Recently", msg.total, 0)
 			m.header.SetStatus(fmt.Sprintf("Loaded %d trends", msg.total))
 			m.lastError = ""
@@ -238,13 +379,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.lastError = msg.err.Error()
 		} else {
 			m.header.SetStatus(fmt.Sprintf("Collected %d trends", msg.result.ItemsCount))
-			cmds = append(cmds, loadTrends(m.apiClient))
+			cmds = append(cmds, loadTrends(m.apiClient), loadAlerts(m.apiClient))
+		}
+
+	case clustersLoadedMsg:
+		if !m.grouped {
+			break
+		}
+		if msg.err != nil {
+			m.header.SetStatus("Error loading clusters")
+			m.lastError = msg.err.Error()
+			break
+		}
+		m.trendsList.SetTitle("STORYLINES")
+		m.trendsList.SetTrends(groupByCluster(m.trends, msg.clusters))
+		m.header.SetStatus(fmt.Sprintf("Grouped into %d storylines", len(msg.clusters)))
+
+	case alertsLoadedMsg:
+		// A server without alerts leaves the badge off rather than
+		// reporting an error.
+		if msg.err == nil {
+			m.header.SetAlerts(msg.unseen)
 		}
 
 	case starCompleteMsg:
 		if msg.err == nil {
 			cmds = append(cmds, loadTrends(m.apiClient))
 		}
+
+	case serverEventMsg:
+		cmds = append(cmds, waitForEvent(m.events))
+		switch msg.Type {
+		case "collection.started":
+			m.header.SetStatus("Collecting...")
+		case "collection.progress":
+			var p struct {
+				SourceID string `json:"source_id"`
+				Done     int    `json:"done"`
+				Total    int    `json:"total"`
+			}
+			if json.Unmarshal(msg.Data, &p) == nil {
+				m.header.SetStatus(fmt.Sprintf("Collecting... %d/%d %s", p.Done, p.Total, p.SourceID))
+			}
+		case "collection.completed":
+			var c struct {
+				ItemsCount int `json:"items_count"`
+				NewCount   int `json:"new_count"`
+			}
+			if json.Unmarshal(msg.Data, &c) == nil {
+				m.header.SetStatus(fmt.Sprintf("Collected %d trends (%d new)", c.ItemsCount, c.NewCount))
+			}
+			// A collection started here reloads on collectCompleteMsg.
+			if !m.collecting {
+				cmds = append(cmds, loadTrends(m.apiClient), loadAlerts(m.apiClient))
+			}
+		case "trend.starred":
+			cmds = append(cmds, loadTrends(m.apiClient))
+		case "alert.raised":
+			cmds = append(cmds, loadAlerts(m.apiClient))
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -280,8 +473,13 @@ func (m model) View() string {
 }
 
 func main() {
+	m := initialModel()
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go m.apiClient.WatchEvents(ctx, m.events)
+
 	p := tea.NewProgram(
-		initialModel(),
+		m,
 		tea.WithAltScreen(),
 	)
 
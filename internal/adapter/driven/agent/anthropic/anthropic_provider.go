@@ -0,0 +1,183 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/domain"
+)
+
+const (
+	DefaultBaseURL   = "https://api.anthropic.com"
+	DefaultMaxTokens = 1024
+	apiVersion       = "2023-06-01"
+)
+
+// Provider talks to the Anthropic Messages API.
+type Provider struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature *float64
+	maxTokens   int
+	client      *http.Client
+}
+
+type messagesRequest struct {
+	Model       string        `json:"model"`
+	System      string        `json:"system,omitempty"`
+	Messages    []llm.Message `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature *float64      `json:"temperature,omitempty"`
+}
+
+type messagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func New(cfg llm.Config) (*Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("anthropic: %w", llm.ErrMissingAPIKey)
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("%w: anthropic: llm.model is required", domain.ErrInvalidConfig)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = DefaultMaxTokens
+	}
+
+	return &Provider{
+		apiKey:      cfg.APIKey,
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+		maxTokens:   cfg.MaxTokens,
+		client: &http.Client{
+			Timeout: cfg.TimeoutOrDefault(),
+		},
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return "anthropic"
+}
+
+func (p *Provider) Model() string {
+	return p.model
+}
+
+// Ping lists the models, which checks the base URL and the API key without
+// spending tokens.
+func (p *Provider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/v1/models", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", apiVersion)
+	return llm.Ping(p.client, "anthropic", req)
+}
+
+func (p *Provider) Chat(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	msgReq := messagesRequest{
+		Model:       p.model,
+		MaxTokens:   p.maxTokens,
+		Temperature: p.temperature,
+	}
+
+	// The Messages API takes the system prompt as a separate field.
+	var system []string
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		msgReq.Messages = append(msgReq.Messages, m)
+	}
+	msgReq.System = strings.Join(system, "\n\n")
+
+	body, err := json.Marshal(msgReq)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", apiVersion)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, llm.NewTransportError("anthropic", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, llm.NewTransportError("anthropic", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, llm.NewHTTPError("anthropic", resp, respBody)
+	}
+
+	var msgResp messagesResponse
+	if err := json.Unmarshal(respBody, &msgResp); err != nil {
+		return nil, llm.NewDecodeError("anthropic", resp, respBody, err)
+	}
+
+	if msgResp.Error != nil {
+		return nil, &llm.APIError{
+			Provider: "anthropic",
+			Status:   resp.StatusCode,
+			Message:  msgResp.Error.Message,
+			Kind:     llm.Classify(resp.StatusCode, msgResp.Error.Message),
+		}
+	}
+
+	var text strings.Builder
+	for _, block := range msgResp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	if text.Len() == 0 {
+		return nil, llm.NewEmptyResponseError("anthropic", resp)
+	}
+
+	return &llm.Response{
+		Content: text.String(),
+		Usage: llm.Usage{
+			PromptTokens:     msgResp.Usage.InputTokens,
+			CompletionTokens: msgResp.Usage.OutputTokens,
+		},
+	}, nil
+}
@@ -0,0 +1,131 @@
+package anthropic_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/anthropic"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/domain"
+)
+
+func newProvider(t *testing.T, handler http.HandlerFunc) *anthropic.Provider {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	provider, err := anthropic.New(llm.Config{APIKey: "test-key", BaseURL: srv.URL, Model: "claude-test"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return provider
+}
+
+func TestProviderChat(t *testing.T) {
+	var got struct {
+		Model     string        `json:"model"`
+		System    string        `json:"system"`
+		Messages  []llm.Message `json:"messages"`
+		MaxTokens int           `json:"max_tokens"`
+	}
+	provider := newProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("request = %s %s, headers %v", r.Method, r.URL.Path, r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{
+			"content": [{"type": "text", "text": "A short "}, {"type": "tool_use"}, {"type": "text", "text": "summary."}],
+			"usage": {"input_tokens": 12, "output_tokens": 3}
+		}`))
+	})
+
+	resp, err := provider.Chat(context.Background(), llm.Request{Messages: []llm.Message{
+		{Role: "system", Content: "Be brief."},
+		{Role: "system", Content: "Use English."},
+		{Role: "user", Content: "Summarize this"},
+	}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Content != "A short summary." {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	if resp.Usage != (llm.Usage{PromptTokens: 12, CompletionTokens: 3}) {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+
+	if got.System != "Be brief.\n\nUse English." {
+		t.Errorf("system = %q, want the system messages joined", got.System)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" {
+		t.Errorf("messages = %+v, want only the user message", got.Messages)
+	}
+	if got.Model != "claude-test" || got.MaxTokens != anthropic.DefaultMaxTokens {
+		t.Errorf("model = %q, max_tokens = %d", got.Model, got.MaxTokens)
+	}
+}
+
+func TestProviderChatTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"auth", http.StatusUnauthorized, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, domain.ErrLLMAuth},
+		{"rate limit", http.StatusTooManyRequests, `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`, domain.ErrLLMRateLimited},
+		{"overloaded", 529, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, domain.ErrLLMUnavailable},
+		{"prompt too long", http.StatusBadRequest, `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 210000 tokens > 200000 maximum"}}`, domain.ErrLLMContextLength},
+		{"no text", http.StatusOK, `{"content":[],"usage":{"input_tokens":5,"output_tokens":0}}`, domain.ErrLLMUnavailable},
+		{"html page with 200", http.StatusOK, `<html><body>Please log in to the proxy</body></html>`, domain.ErrLLMUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := provider.Chat(context.Background(), llm.Request{
+				Messages: []llm.Message{{Role: "user", Content: "hi"}},
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderPing(t *testing.T) {
+	for status, want := range map[int]error{
+		http.StatusOK:           nil,
+		http.StatusUnauthorized: domain.ErrLLMAuth,
+	} {
+		provider := newProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/v1/models" || r.Header.Get("x-api-key") != "test-key" {
+				t.Errorf("ping request = %s %s, headers %v", r.Method, r.URL.Path, r.Header)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(`{"data":[]}`))
+		})
+
+		err := provider.Ping(context.Background())
+		if (want == nil && err != nil) || (want != nil && !errors.Is(err, want)) {
+			t.Errorf("Ping with HTTP %d = %v, want %v", status, err, want)
+		}
+	}
+}
+
+func TestNewRequiresAPIKeyAndModel(t *testing.T) {
+	if _, err := anthropic.New(llm.Config{Model: "claude-test"}); !errors.Is(err, llm.ErrMissingAPIKey) {
+		t.Errorf("New without api key = %v", err)
+	}
+	if _, err := anthropic.New(llm.Config{APIKey: "test"}); !errors.Is(err, domain.ErrInvalidConfig) {
+		t.Errorf("New without model = %v", err)
+	}
+}
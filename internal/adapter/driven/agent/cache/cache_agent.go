@@ -0,0 +1,329 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	DefaultTTL      = 30 * 24 * time.Hour
+	DefaultMaxBytes = 64 << 20
+)
+
+// Agent is a service.LLMAgent that answers repeated prompts from disk. Entries
+// are keyed by provider, model, operation and the rendered prompt, so a
+// change to any of them misses the cache.
+type Agent struct {
+	next     service.LLMAgent
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+
+	// mu serialises changes to the directory. Readers need no lock since
+	// entries are replaced by rename.
+	mu sync.Mutex
+	// size is the bytes used by entries, counted on the first store; -1
+	// until then.
+	size   int64
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type Stats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
+
+type entry struct {
+	Operation string          `json:"operation"`
+	Provider  string          `json:"provider"`
+	Model     string          `json:"model"`
+	CreatedAt time.Time       `json:"created_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+type Option func(*Agent)
+
+func WithTTL(ttl time.Duration) Option {
+	return func(a *Agent) {
+		if ttl > 0 {
+			a.ttl = ttl
+		}
+	}
+}
+
+// WithMaxBytes bounds the cache directory; the oldest entries are removed
+// once it grows past n bytes.
+func WithMaxBytes(n int64) Option {
+	return func(a *Agent) {
+		if n > 0 {
+			a.maxBytes = n
+		}
+	}
+}
+
+func New(next service.LLMAgent, dir string, opts ...Option) (*Agent, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	a := &Agent{
+		next:     next,
+		dir:      dir,
+		ttl:      DefaultTTL,
+		maxBytes: DefaultMaxBytes,
+		size:     -1,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+func (a *Agent) Name() string  { return a.next.Name() }
+func (a *Agent) Model() string { return a.next.Model() }
+
+func (a *Agent) Summarize(ctx context.Context, prompt string) (string, error) {
+	var out string
+	err := a.do(ctx, "summarize", prompt, &out, func() (any, error) {
+		return a.next.Summarize(ctx, prompt)
+	})
+	return out, err
+}
+
+func (a *Agent) Suggest(ctx context.Context, req service.SuggestRequest) ([]service.TopicSuggestion, error) {
+	var out []service.TopicSuggestion
+	key := req.Prompt + "\x00" + strings.Join(req.TrendIDs, ",")
+	err := a.do(ctx, "suggest", key, &out, func() (any, error) {
+		return a.next.Suggest(ctx, req)
+	})
+	return out, err
+}
+
+func (a *Agent) Tag(ctx context.Context, prompt string) (*service.TrendTags, error) {
+	var out *service.TrendTags
+	err := a.do(ctx, "tag", prompt, &out, func() (any, error) {
+		return a.next.Tag(ctx, prompt)
+	})
+	return out, err
+}
+
+func (a *Agent) Outline(ctx context.Context, req service.OutlineRequest) (*entity.Outline, error) {
+	var out *entity.Outline
+	key := req.Prompt + "\x00" + strings.Join(req.TrendIDs, ",")
+	err := a.do(ctx, "outline", key, &out, func() (any, error) {
+		return a.next.Outline(ctx, req)
+	})
+	return out, err
+}
+
+func (a *Agent) Draft(ctx context.Context, prompt string) (string, error) {
+	var out string
+	err := a.do(ctx, "draft", prompt, &out, func() (any, error) {
+		return a.next.Draft(ctx, prompt)
+	})
+	return out, err
+}
+
+func (a *Agent) Stats() Stats {
+	a.mu.Lock()
+	files, size := a.files()
+	a.mu.Unlock()
+
+	return Stats{
+		Hits:    a.hits.Load(),
+		Misses:  a.misses.Load(),
+		Entries: len(files),
+		Bytes:   size,
+	}
+}
+
+// do answers from the cache when possible, otherwise calls the wrapped agent
+// and stores the result. service.SkipCache(ctx) forces a fresh call.
+func (a *Agent) do(ctx context.Context, operation, prompt string, out any, call func() (any, error)) error {
+	key := a.key(operation, prompt)
+
+	if !service.CacheSkipped(ctx) && a.lookup(key, out) {
+		a.hits.Add(1)
+		return nil
+	}
+	a.misses.Add(1)
+
+	value, err := call()
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	a.store(key, operation, raw)
+
+	return json.Unmarshal(raw, out)
+}
+
+func (a *Agent) key(operation, prompt string) string {
+	h := sha256.New()
+	for _, part := range []string{a.next.Name(), a.next.Model(), operation, prompt} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (a *Agent) path(key string) string {
+	return filepath.Join(a.dir, key+".json")
+}
+
+func (a *Agent) lookup(key string, out any) bool {
+	e, ok := a.read(key)
+	if !ok {
+		return false
+	}
+	if time.Since(e.CreatedAt) >= a.ttl {
+		a.expire(key)
+		return false
+	}
+	return json.Unmarshal(e.Value, out) == nil
+}
+
+func (a *Agent) read(key string) (*entry, bool) {
+	data, err := os.ReadFile(a.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// expire removes key if it is still expired once the lock is held, as a
+// concurrent store may have just refreshed it.
+func (a *Agent) expire(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e, ok := a.read(key)
+	if !ok || time.Since(e.CreatedAt) < a.ttl {
+		return
+	}
+	a.remove(a.path(key))
+}
+
+func (a *Agent) store(key, operation string, value json.RawMessage) {
+	data, err := json.MarshalIndent(entry{
+		Operation: operation,
+		Provider:  a.next.Name(),
+		Model:     a.next.Model(),
+		CreatedAt: time.Now().UTC(),
+		Value:     value,
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Write a temporary file and rename it over the entry, so that
+	// readers never see a partly written one.
+	tmp, err := os.CreateTemp(a.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	if a.size < 0 {
+		_, a.size = a.files()
+	}
+	path := a.path(key)
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	a.size += int64(len(data)) - replaced
+
+	if a.size > a.maxBytes {
+		a.prune()
+	}
+}
+
+// remove deletes an entry and counts its bytes off. The caller holds a.mu.
+func (a *Agent) remove(path string) {
+	info, err := os.Stat(path)
+	if err != nil || os.Remove(path) != nil {
+		return
+	}
+	if a.size >= 0 {
+		a.size -= info.Size()
+	}
+}
+
+// prune removes the oldest entries until the cache fits in maxBytes. The
+// caller holds a.mu.
+func (a *Agent) prune() {
+	files, size := a.files()
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, f := range files {
+		if size <= a.maxBytes {
+			break
+		}
+		if os.Remove(filepath.Join(a.dir, f.Name())) == nil {
+			size -= f.Size()
+		}
+	}
+	a.size = size
+}
+
+func (a *Agent) files() ([]os.FileInfo, int64) {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, 0
+	}
+
+	var (
+		files []os.FileInfo
+		size  int64
+	)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+		size += info.Size()
+	}
+	return files, size
+}
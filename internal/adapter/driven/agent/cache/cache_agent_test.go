@@ -0,0 +1,94 @@
+package cache_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/agent/cache"
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/app/service"
+)
+
+func TestAgentCachesByPrompt(t *testing.T) {
+	ctx := context.Background()
+	provider := fake.New()
+	a, err := cache.New(llm.NewAgent(provider), t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	first, err := a.Summarize(ctx, "Summarize: Go 1.26 released")
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	second, _ := a.Summarize(ctx, "Summarize: Go 1.26 released")
+	if second != first || len(provider.Calls()) != 1 {
+		t.Errorf("second call was not served from cache: %q, %d calls", second, len(provider.Calls()))
+	}
+
+	a.Summarize(ctx, "Summarize: Rust 2.0 released")
+	a.Summarize(service.SkipCache(ctx), "Summarize: Go 1.26 released")
+	if len(provider.Calls()) != 3 {
+		t.Errorf("got %d calls, want a miss for the new prompt and the skipped lookup", len(provider.Calls()))
+	}
+
+	if got := a.Stats(); got.Hits != 1 || got.Misses != 3 || got.Entries != 2 {
+		t.Errorf("stats = %+v", got)
+	}
+}
+
+func TestAgentExpiresAndPrunes(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider := fake.New()
+	a, err := cache.New(llm.NewAgent(provider), dir, cache.WithTTL(time.Nanosecond), cache.WithMaxBytes(1))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	a.Suggest(ctx, service.SuggestRequest{Prompt: "- Go 1.26 released"})
+	a.Suggest(ctx, service.SuggestRequest{Prompt: "- Go 1.26 released"})
+	if len(provider.Calls()) != 2 {
+		t.Errorf("expired entry was reused: %d calls", len(provider.Calls()))
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("cache over max_bytes kept %d entries", len(entries))
+	}
+}
+
+func TestAgentConcurrentReadsSeeWholeEntries(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	a, err := cache.New(llm.NewAgent(fake.New()), dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	want, _ := a.Summarize(ctx, "Summarize: Go 1.26 released")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := ctx
+			if i%2 == 0 {
+				c = service.SkipCache(ctx)
+			}
+			if got, err := a.Summarize(c, "Summarize: Go 1.26 released"); err != nil || got != want {
+				t.Errorf("Summarize = %q, %v", got, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("cache holds %d files, want the entry alone", len(entries))
+	}
+}
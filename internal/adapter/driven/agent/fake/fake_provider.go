@@ -0,0 +1,359 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+)
+
+// Rule answers every request whose last message contains Match (and whose
+// operation equals Operation, if set) with Response. A rule with Error fails
+// with an llm.APIError carrying Status (default 500) and RetryAfter seconds.
+// Times limits how often the rule applies; 0 means always.
+type Rule struct {
+	Operation  string `yaml:"operation"`
+	Match      string `yaml:"match"`
+	Response   string `yaml:"response"`
+	Error      string `yaml:"error"`
+	Status     int    `yaml:"status"`
+	RetryAfter int    `yaml:"retry_after"`
+	Times      int    `yaml:"times"`
+
+	used int
+}
+
+// Provider is a deterministic, offline llm.Provider. Requests are answered
+// from, in order: matching rules, the scripted queue, and finally a canned
+// response derived from the prompt so that the same input always produces
+// the same output.
+type Provider struct {
+	mu     sync.Mutex
+	model  string
+	rules  []Rule
+	script []string
+	calls  []llm.Request
+}
+
+func New(script ...string) *Provider {
+	return &Provider{
+		model:  "fake",
+		script: script,
+	}
+}
+
+// FromConfig builds the provider for llm.provider: fake. cfg.Fixtures may
+// point to a YAML file with a top-level "rules" list.
+func FromConfig(cfg llm.Config) (*Provider, error) {
+	p := New()
+	if cfg.Model != "" {
+		p.model = cfg.Model
+	}
+	if cfg.Fixtures == "" {
+		return p, nil
+	}
+
+	rules, err := LoadRules(cfg.Fixtures)
+	if err != nil {
+		return nil, err
+	}
+	p.rules = rules
+	return p, nil
+}
+
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake llm fixtures: %w", err)
+	}
+
+	var file struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse fake llm fixtures: %w", err)
+	}
+	return file.Rules, nil
+}
+
+func (p *Provider) Name() string {
+	return "fake"
+}
+
+func (p *Provider) Model() string {
+	return p.model
+}
+
+// On adds a rule matching prompts that contain match.
+func (p *Provider) On(match, response string) *Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = append(p.rules, Rule{Match: match, Response: response})
+	return p
+}
+
+// Rules appends rules, e.g. ones that fail with an API error.
+func (p *Provider) Rules(rules ...Rule) *Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = append(p.rules, rules...)
+	return p
+}
+
+// Script queues responses returned in order once no rule matches.
+func (p *Provider) Script(responses ...string) *Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.script = append(p.script, responses...)
+	return p
+}
+
+// Calls returns every request received so far.
+func (p *Provider) Calls() []llm.Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]llm.Request(nil), p.calls...)
+}
+
+func (p *Provider) Chat(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls = append(p.calls, req)
+
+	prompt := ""
+	if len(req.Messages) > 0 {
+		prompt = req.Messages[len(req.Messages)-1].Content
+	}
+
+	for i := range p.rules {
+		r := &p.rules[i]
+		if r.Operation != "" && r.Operation != req.Operation {
+			continue
+		}
+		if r.Times > 0 && r.used >= r.Times {
+			continue
+		}
+		if strings.Contains(prompt, r.Match) {
+			r.used++
+			if r.Error != "" {
+				return nil, r.apiError()
+			}
+			return respond(prompt, r.Response), nil
+		}
+	}
+
+	if len(p.script) > 0 {
+		content := p.script[0]
+		p.script = p.script[1:]
+		return respond(prompt, content), nil
+	}
+
+	return respond(prompt, canned(req.Operation, prompt)), nil
+}
+
+func (r *Rule) apiError() *llm.APIError {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	return &llm.APIError{
+		Provider:   "fake",
+		Status:     status,
+		Message:    r.Error,
+		RetryAfter: time.Duration(r.RetryAfter) * time.Second,
+		Kind:       llm.Classify(status, r.Error),
+	}
+}
+
+// respond reports roughly one token per word so usage accounting has
+// something deterministic to count.
+func respond(prompt, content string) *llm.Response {
+	return &llm.Response{
+		Content: content,
+		Usage: llm.Usage{
+			PromptTokens:     len(strings.Fields(prompt)),
+			CompletionTokens: len(strings.Fields(content)),
+		},
+	}
+}
+
+func canned(operation, prompt string) string {
+	switch operation {
+	case llm.OperationSuggest:
+		return cannedSuggestions(prompt)
+	case llm.OperationTag:
+		return cannedTags(prompt)
+	case llm.OperationOutline:
+		return cannedOutline(prompt)
+	case llm.OperationDraft:
+		return cannedDraft(prompt)
+	default:
+		return cannedSummary(prompt)
+	}
+}
+
+// cannedSummary echoes the longest line of the prompt, which for the
+// summarizer templates is the content being summarised.
+func cannedSummary(prompt string) string {
+	longest := ""
+	for _, line := range strings.Split(prompt, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > len(longest) {
+			longest = line
+		}
+	}
+	if len(longest) > 200 {
+		longest = strings.ToValidUTF8(longest[:200], "") + "..."
+	}
+	return fmt.Sprintf("Summary [%08x]: %s", checksum(prompt), longest)
+}
+
+// cannedSuggestions turns up to three "- " bullet lines of the prompt into a
+// JSON suggestion list.
+func cannedSuggestions(prompt string) string {
+	type suggestion struct {
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Score       float64  `json:"score"`
+		Topics      []string `json:"topics"`
+	}
+
+	var out []suggestion
+	for _, line := range strings.Split(prompt, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		topic := strings.TrimSpace(line[2:])
+
+		// Bullets written as "[id] title" cite the trend ID.
+		ids := []string{}
+		if strings.HasPrefix(topic, "[") {
+			if end := strings.Index(topic, "]"); end > 0 {
+				ids = append(ids, topic[1:end])
+				topic = strings.TrimSpace(topic[end+1:])
+			}
+		}
+
+		out = append(out, suggestion{
+			Title:       "Why it matters: " + topic,
+			Description: "A closer look at " + topic + ".",
+			Score:       0.9 - float64(len(out))*0.1,
+			Topics:      ids,
+		})
+		if len(out) == 3 {
+			break
+		}
+	}
+	if len(out) == 0 {
+		topic := fmt.Sprintf("Topic %08x", checksum(prompt))
+		out = append(out, suggestion{
+			Title:       "Why it matters: " + topic,
+			Description: "A closer look at " + topic + ".",
+			Score:       0.9,
+			Topics:      []string{},
+		})
+	}
+
+	data, _ := json.MarshalIndent(map[string]any{"suggestions": out}, "", "  ")
+	return string(data)
+}
+
+// cannedTags uses the longest words of the title line as tags.
+func cannedTags(prompt string) string {
+	title := ""
+	for _, line := range strings.Split(prompt, "\n") {
+		if strings.HasPrefix(line, "Title: ") {
+			title = strings.TrimPrefix(line, "Title: ")
+			break
+		}
+	}
+
+	var tags []string
+	for _, word := range strings.Fields(strings.ToLower(title)) {
+		word = strings.Trim(word, ".,:;!?()[]\"'")
+		if len(word) >= 4 && len(tags) < 3 {
+			tags = append(tags, word)
+		}
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	data, _ := json.Marshal(map[string]any{"category": "technology", "tags": tags})
+	return string(data)
+}
+
+// cannedOutline gives each "[id] title" source line of the prompt a section
+// citing it.
+func cannedOutline(prompt string) string {
+	type section struct {
+		Heading string   `json:"heading"`
+		Points  []string `json:"points"`
+		Sources []string `json:"sources"`
+	}
+
+	sections := []section{}
+	for _, line := range strings.Split(prompt, "\n") {
+		line = strings.TrimSpace(line)
+		end := strings.Index(line, "]")
+		if !strings.HasPrefix(line, "[") || end <= 1 {
+			continue
+		}
+		title := strings.TrimSpace(line[end+1:])
+		sections = append(sections, section{
+			Heading: title,
+			Points:  []string{"What happened: " + title},
+			Sources: []string{line[1:end]},
+		})
+	}
+
+	title := fmt.Sprintf("Post %08x", checksum(prompt))
+	if len(sections) > 0 {
+		title = "Why it matters: " + sections[0].Heading
+	}
+
+	data, _ := json.MarshalIndent(map[string]any{"title": title, "sections": sections}, "", "  ")
+	return string(data)
+}
+
+// cannedDraft copies the headings of the outline in the prompt, with a
+// paragraph under each and the citations listed for it.
+func cannedDraft(prompt string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(prompt, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "# "):
+			b.WriteString(line + "\n")
+		case strings.HasPrefix(line, "## "):
+			fmt.Fprintf(&b, "\n%s\n\nDraft paragraph [%08x].\n", line, checksum(line))
+		case strings.HasPrefix(line, "Sources: ["):
+			b.WriteString("See " + strings.TrimPrefix(line, "Sources: ") + ".\n")
+		}
+	}
+	if b.Len() == 0 {
+		return fmt.Sprintf("# Draft %08x\n", checksum(prompt))
+	}
+	return b.String()
+}
+
+func checksum(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
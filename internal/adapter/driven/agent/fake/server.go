@@ -0,0 +1,179 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+)
+
+// Server is an OpenAI-compatible /chat/completions stand-in backed by a
+// Provider. Point an openai or zai provider's base_url at Server.URL to
+// exercise the real HTTP client without network access.
+//
+// The fake package is linked into the server, so Server listens on its own
+// rather than through net/http/httptest, which would pull in package testing.
+type Server struct {
+	URL    string
+	server *http.Server
+
+	provider *Provider
+
+	mu       sync.Mutex
+	requests []ChatRequest
+}
+
+type ChatRequest struct {
+	Model          string        `json:"model"`
+	Messages       []llm.Message `json:"messages"`
+	ResponseFormat *struct {
+		Type       string `json:"type"`
+		JSONSchema *struct {
+			Name string `json:"name"`
+		} `json:"json_schema"`
+	} `json:"response_format"`
+}
+
+// operation recovers the llm operation, which the OpenAI wire format does
+// not carry, so that canned responses fit the request. Structured calls are
+// known by their schema, sent as response_format or in the instructions;
+// tagging and drafting by what the default prompts ask for.
+func (r *ChatRequest) operation() string {
+	schemas := map[string]string{
+		llm.SuggestionSchema.Name: llm.OperationSuggest,
+		llm.OutlineSchema.Name:    llm.OperationOutline,
+	}
+	if f := r.ResponseFormat; f != nil && f.JSONSchema != nil {
+		if op, ok := schemas[f.JSONSchema.Name]; ok {
+			return op
+		}
+	}
+
+	prompt := ""
+	for _, m := range r.Messages {
+		switch {
+		case strings.Contains(m.Content, string(llm.SuggestionSchema.Schema)):
+			return llm.OperationSuggest
+		case strings.Contains(m.Content, string(llm.OutlineSchema.Schema)):
+			return llm.OperationOutline
+		}
+		if m.Role == "user" && prompt == "" {
+			prompt = m.Content
+		}
+	}
+
+	switch {
+	case strings.Contains(prompt, `"category"`) && strings.Contains(prompt, `"tags"`):
+		return llm.OperationTag
+	case strings.Contains(prompt, "following this outline"):
+		return llm.OperationDraft
+	}
+	return llm.OperationSummarize
+}
+
+func NewServer(provider *Provider) *Server {
+	if provider == nil {
+		provider = New()
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("fake: listen: %v", err))
+	}
+
+	s := &Server{provider: provider}
+	s.URL = "http://" + ln.Addr().String()
+	s.server = &http.Server{Handler: http.HandlerFunc(s.handle)}
+	go s.server.Serve(ln)
+	return s
+}
+
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+func (s *Server) Provider() *Provider {
+	return s.provider
+}
+
+// Requests returns the decoded chat requests received so far.
+func (s *Server) Requests() []ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ChatRequest(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/models" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"object": "list",
+			"data":   []map[string]string{{"id": "fake", "object": "model"}},
+		})
+		return
+	}
+	if r.Method != http.MethodPost || r.URL.Path != "/chat/completions" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	resp, err := s.provider.Chat(r.Context(), llm.Request{Operation: req.operation(), Messages: req.Messages})
+	if err != nil {
+		status, message := http.StatusInternalServerError, err.Error()
+		var apiErr *llm.APIError
+		if errors.As(err, &apiErr) {
+			status, message = apiErr.Status, apiErr.Message
+			if apiErr.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(apiErr.RetryAfter.Seconds())))
+			}
+		}
+		writeError(w, status, message)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":     "chatcmpl-fake",
+		"object": "chat.completion",
+		"model":  req.Model,
+		"choices": []map[string]any{
+			{
+				"index":         0,
+				"finish_reason": "stop",
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": resp.Content,
+				},
+			},
+		},
+		"usage": map[string]int{
+			"prompt_tokens":     resp.Usage.PromptTokens,
+			"completion_tokens": resp.Usage.CompletionTokens,
+			"total_tokens":      resp.Usage.PromptTokens + resp.Usage.CompletionTokens,
+		},
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]string{"message": message},
+	})
+}
@@ -0,0 +1,29 @@
+package glm5
+
+import (
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/agent/openai"
+)
+
+const (
+	DefaultBaseURL = "https://api.z.ai/v1"
+	DefaultModel   = "glm-5"
+)
+
+// New returns an OpenAI-compatible provider preconfigured for z.ai's GLM
+// models, which accept JSON mode but not JSON schemas.
+func New(cfg llm.Config) (*openai.Provider, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = "zai"
+	}
+	if cfg.ResponseFormat == "" {
+		cfg.ResponseFormat = openai.FormatJSONObject
+	}
+	return openai.New(cfg)
+}
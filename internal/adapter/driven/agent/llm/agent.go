@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/app/metrics"
+	"r3f-trends/internal/app/service"
+)
+
+type Agent struct {
+	provider Provider
+	usage    service.UsageRecorder
+}
+
+type AgentOption func(*Agent)
+
+// WithUsageRecorder reports the token usage of every provider call.
+func WithUsageRecorder(r service.UsageRecorder) AgentOption {
+	return func(a *Agent) {
+		a.usage = r
+	}
+}
+
+func NewAgent(provider Provider, opts ...AgentOption) *Agent {
+	a := &Agent{provider: provider}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *Agent) Name() string {
+	return a.provider.Name()
+}
+
+func (a *Agent) Model() string {
+	return a.provider.Model()
+}
+
+func (a *Agent) Summarize(ctx context.Context, prompt string) (string, error) {
+	return a.complete(ctx, OperationSummarize, prompt)
+}
+
+func (a *Agent) Tag(ctx context.Context, prompt string) (*service.TrendTags, error) {
+	response, err := a.complete(ctx, OperationTag, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTags(response)
+}
+
+func (a *Agent) Draft(ctx context.Context, prompt string) (string, error) {
+	return a.complete(ctx, OperationDraft, prompt)
+}
+
+func (a *Agent) complete(ctx context.Context, operation, prompt string) (string, error) {
+	return a.chat(ctx, Request{
+		Operation: operation,
+		Messages: []Message{
+			{Role: "user", Content: prompt},
+		},
+	})
+}
+
+func (a *Agent) chat(ctx context.Context, req Request) (string, error) {
+	started := time.Now()
+	resp, err := a.provider.Chat(ctx, req)
+	d := time.Since(started)
+	if err != nil {
+		metrics.LLMDuration.Observe(d.Seconds(), a.provider.Name(), req.Operation, "error")
+		slog.ErrorContext(ctx, "llm call failed",
+			"provider", a.provider.Name(), "model", a.provider.Model(), "operation", req.Operation, "duration", d, "error", err)
+		return "", err
+	}
+	metrics.LLMDuration.Observe(d.Seconds(), a.provider.Name(), req.Operation, "ok")
+	slog.DebugContext(ctx, "llm call",
+		"provider", a.provider.Name(), "model", a.provider.Model(), "operation", req.Operation, "duration", d,
+		"prompt_tokens", resp.Usage.PromptTokens, "completion_tokens", resp.Usage.CompletionTokens)
+	metrics.LLMTokens.Add(float64(resp.Usage.PromptTokens), a.provider.Name(), a.provider.Model(), "prompt")
+	metrics.LLMTokens.Add(float64(resp.Usage.CompletionTokens), a.provider.Name(), a.provider.Model(), "completion")
+
+	if a.usage != nil {
+		a.usage.Record(ctx, service.UsageEvent{
+			Provider:         a.provider.Name(),
+			Model:            a.provider.Model(),
+			Operation:        req.Operation,
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+		})
+	}
+
+	return resp.Content, nil
+}
+
+// Ping checks that the provider's API answers and accepts the key, without
+// spending tokens. Providers without an API, like the fake one, always pass.
+func (a *Agent) Ping(ctx context.Context) error {
+	p := a.provider
+	for {
+		w, ok := p.(interface{ Unwrap() Provider })
+		if !ok {
+			break
+		}
+		p = w.Unwrap()
+	}
+	pinger, ok := p.(interface {
+		Ping(ctx context.Context) error
+	})
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return pinger.Ping(ctx)
+}
+
+func parseTags(response string) (*service.TrendTags, error) {
+	jsonStart := strings.Index(response, "{")
+	jsonEnd := strings.LastIndex(response, "}")
+	if jsonStart == -1 || jsonEnd < jsonStart {
+		return nil, fmt.Errorf("no JSON object in tag response")
+	}
+
+	var tags service.TrendTags
+	if err := json.Unmarshal([]byte(response[jsonStart:jsonEnd+1]), &tags); err != nil {
+		return nil, fmt.Errorf("invalid tag response: %w", err)
+	}
+
+	tags.Category = strings.ToLower(strings.TrimSpace(tags.Category))
+	seen := make(map[string]bool, len(tags.Tags))
+	cleaned := tags.Tags[:0]
+	for _, tag := range tags.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		cleaned = append(cleaned, tag)
+	}
+	tags.Tags = cleaned
+
+	return &tags, nil
+}
@@ -0,0 +1,97 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+)
+
+func TestAgentSuggest(t *testing.T) {
+	ids := []string{"hn-1", "hn-2"}
+	valid := `{"suggestions": [{"title": "Go 1.26", "description": "What changed", "score": 0.9, "topics": ["hn-1"]}]}`
+	want := []service.TopicSuggestion{
+		{Title: "Go 1.26", Description: "What changed", Score: 0.9, Topics: []string{"hn-1"}},
+	}
+
+	tests := []struct {
+		name      string
+		responses []string
+		want      []service.TopicSuggestion
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "schema object", responses: []string{valid}, want: want, wantCalls: 1},
+		{
+			name: "bare array wrapped in prose and a code fence",
+			responses: []string{"Here you go:\n```json\n" +
+				`[{"title": "Go 1.26", "description": "What changed", "score": 0.9, "topics": ["hn-1"]}]` +
+				"\n```\nEnjoy!"},
+			want:      want,
+			wantCalls: 1,
+		},
+		{name: "free text is repaired", responses: []string{"## Write about eBPF", valid}, want: want, wantCalls: 2},
+		{
+			name:      "unknown trend ID is repaired",
+			responses: []string{`{"suggestions": [{"title": "X", "description": "x", "score": 0.5, "topics": ["hn-9"]}]}`, valid},
+			want:      want,
+			wantCalls: 2,
+		},
+		{
+			name:      "gives up after the repair attempt",
+			responses: []string{`{"suggestions": []}`, `{"suggestions": [{"title": "", "description": "x", "score": 2, "topics": []}]}`},
+			wantErr:   domain.ErrLLMInvalidOutput,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := fake.New(tt.responses...)
+			agent := llm.NewAgent(provider)
+
+			got, err := agent.Suggest(context.Background(), service.SuggestRequest{Prompt: "prompt", TrendIDs: ids})
+			if !errors.Is(err, tt.wantErr) || (err != nil && tt.wantErr == nil) {
+				t.Fatalf("Suggest error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest() = %#v, want %#v", got, tt.want)
+			}
+
+			calls := provider.Calls()
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("got %d calls, want %d", len(calls), tt.wantCalls)
+			}
+			if calls[0].Operation != llm.OperationSuggest || calls[0].Schema == nil {
+				t.Errorf("suggest call without schema: %#v", calls[0])
+			}
+			if tt.wantCalls > 1 {
+				last := calls[1].Messages[len(calls[1].Messages)-1]
+				if !strings.Contains(last.Content, "invalid") {
+					t.Errorf("repair prompt = %q", last.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestAgentSummarizeIsDeterministic(t *testing.T) {
+	agent := llm.NewAgent(fake.New())
+	prompt := "Summarize:\n\nRust 2.0 ships a new borrow checker with better diagnostics.\n"
+
+	first, err := agent.Summarize(context.Background(), prompt)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	second, _ := agent.Summarize(context.Background(), prompt)
+
+	if first != second {
+		t.Errorf("fake summaries differ: %q vs %q", first, second)
+	}
+}
@@ -0,0 +1,193 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/domain"
+)
+
+// APIError is a failed provider call. Kind is one of the domain.ErrLLM*
+// errors (or nil when the failure has no specific meaning), so callers can
+// use errors.Is without knowing the provider.
+type APIError struct {
+	Provider   string
+	Status     int
+	Message    string
+	RetryAfter time.Duration
+	Kind       error
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Provider)
+	if e.Status != 0 {
+		fmt.Fprintf(&b, ": HTTP %d", e.Status)
+	}
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+	if e.Kind != nil {
+		fmt.Fprintf(&b, " (%v)", e.Kind)
+	}
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// Retryable reports whether the call may succeed if repeated.
+func (e *APIError) Retryable() bool {
+	return e.Kind == domain.ErrLLMRateLimited || e.Kind == domain.ErrLLMUnavailable
+}
+
+// NewHTTPError builds the APIError for a non-2xx provider response. body may
+// be JSON in any of the common error shapes, plain text or HTML.
+func NewHTTPError(provider string, resp *http.Response, body []byte) *APIError {
+	message := errorMessage(body)
+	return &APIError{
+		Provider:   provider,
+		Status:     resp.StatusCode,
+		Message:    message,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Kind:       Classify(resp.StatusCode, message),
+	}
+}
+
+// NewDecodeError is for a successful status with a body that is not the
+// provider's JSON, such as a proxy's HTML page. It is treated as an outage.
+func NewDecodeError(provider string, resp *http.Response, body []byte, err error) *APIError {
+	return &APIError{
+		Provider: provider,
+		Status:   resp.StatusCode,
+		Message:  fmt.Sprintf("invalid response: %v: %s", err, errorMessage(body)),
+		Kind:     domain.ErrLLMUnavailable,
+	}
+}
+
+// NewEmptyResponseError is for a successful response that carries no
+// completion. Like an undecodable body it is treated as an outage, so the
+// call is retried.
+func NewEmptyResponseError(provider string, resp *http.Response) *APIError {
+	return &APIError{
+		Provider: provider,
+		Status:   resp.StatusCode,
+		Message:  "no response from API",
+		Kind:     domain.ErrLLMUnavailable,
+	}
+}
+
+// Ping sends req, a cheap authenticated call such as listing models, and
+// returns an APIError unless the provider answers with a 2xx status.
+func Ping(client *http.Client, provider string, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		if req.Context().Err() != nil {
+			return req.Context().Err()
+		}
+		return NewTransportError(provider, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return NewHTTPError(provider, resp, body)
+	}
+	return nil
+}
+
+// NewTransportError wraps a failure to reach the provider at all.
+func NewTransportError(provider string, err error) *APIError {
+	return &APIError{
+		Provider: provider,
+		Message:  err.Error(),
+		Kind:     domain.ErrLLMUnavailable,
+	}
+}
+
+// Classify maps an HTTP status and error message to a domain error.
+func Classify(status int, message string) error {
+	if isContextLength(message) {
+		return domain.ErrLLMContextLength
+	}
+
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return domain.ErrLLMAuth
+	case status == http.StatusTooManyRequests:
+		return domain.ErrLLMRateLimited
+	case status == http.StatusRequestEntityTooLarge:
+		return domain.ErrLLMContextLength
+	// 529 is Anthropic's "overloaded".
+	case status >= 500:
+		return domain.ErrLLMUnavailable
+	}
+	return nil
+}
+
+func isContextLength(message string) bool {
+	m := strings.ToLower(message)
+	for _, s := range []string{
+		"context length",
+		"context_length_exceeded",
+		"maximum context",
+		"context window",
+		"prompt is too long",
+		"too many tokens",
+	} {
+		if strings.Contains(m, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns 0 when the header is missing or invalid.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+func errorMessage(body []byte) string {
+	var shapes struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &shapes) == nil {
+		var nested struct {
+			Message string `json:"message"`
+		}
+		var flat string
+		switch {
+		case json.Unmarshal(shapes.Error, &nested) == nil && nested.Message != "":
+			return nested.Message
+		case json.Unmarshal(shapes.Error, &flat) == nil && flat != "":
+			return flat
+		case shapes.Message != "":
+			return shapes.Message
+		}
+	}
+
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if len(text) > 200 {
+		text = strings.ToValidUTF8(text[:200], "") + "..."
+	}
+	return text
+}
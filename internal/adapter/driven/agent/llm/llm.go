@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrMissingAPIKey = errors.New("llm api key is required")
+
+type Config struct {
+	Provider    string
+	Model       string
+	APIKey      string
+	BaseURL     string
+	Temperature *float64
+	MaxTokens   int
+	Timeout     time.Duration
+	// Fixtures is a file of canned responses for the fake provider.
+	Fixtures string
+	// ResponseFormat selects how structured output is requested from
+	// OpenAI-compatible APIs: "json_schema", "json_object" or "none".
+	// Empty uses the provider's default.
+	ResponseFormat string
+	// Retry applies to every provider; zero MaxAttempts means
+	// DefaultRetryPolicy.
+	Retry RetryPolicy
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+const (
+	OperationSummarize = "summarize"
+	OperationSuggest   = "suggest"
+	OperationTag       = "tag"
+	OperationOutline   = "outline"
+	OperationDraft     = "draft"
+)
+
+type Request struct {
+	Operation string
+	Messages  []Message
+	// Schema, when set, asks for a JSON response matching it on providers
+	// that support structured output. The prompt still has to describe the
+	// format for the others.
+	Schema *Schema
+}
+
+type Schema struct {
+	Name   string
+	Schema json.RawMessage
+}
+
+type Response struct {
+	Content string
+	Usage   Usage
+}
+
+// Usage is the token count reported by the provider for one call.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Provider is a single chat completion backend. Agent builds the
+// summarize/suggest behaviour on top of it, so a new backend only has to
+// translate a Request into its wire format.
+type Provider interface {
+	Name() string
+	Model() string
+	Chat(ctx context.Context, req Request) (*Response, error)
+}
+
+func (c Config) TimeoutOrDefault() time.Duration {
+	if c.Timeout <= 0 {
+		return 60 * time.Second
+	}
+	return c.Timeout
+}
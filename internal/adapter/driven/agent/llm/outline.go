@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+var OutlineSchema = Schema{
+	Name: "post_outline",
+	Schema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "title": {"type": "string"},
+    "sections": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "heading": {"type": "string"},
+          "points": {"type": "array", "items": {"type": "string"}},
+          "sources": {"type": "array", "items": {"type": "string"}}
+        },
+        "required": ["heading", "points", "sources"],
+        "additionalProperties": false
+      }
+    }
+  },
+  "required": ["title", "sections"],
+  "additionalProperties": false
+}`),
+}
+
+func (a *Agent) Outline(ctx context.Context, req service.OutlineRequest) (*entity.Outline, error) {
+	instructions := schemaInstructions(&OutlineSchema)
+	if len(req.TrendIDs) > 0 {
+		instructions += "\n\nsources lists the IDs of the trends each section draws on, chosen from: " +
+			strings.Join(req.TrendIDs, ", ") + "."
+	}
+
+	var outline *entity.Outline
+	err := a.structured(ctx, OperationOutline, req.Prompt, instructions, &OutlineSchema, func(response string) error {
+		var err error
+		outline, err = parseOutline(response, req.TrendIDs)
+		return err
+	})
+	return outline, err
+}
+
+func parseOutline(response string, trendIDs []string) (*entity.Outline, error) {
+	raw := extractJSON(response)
+	if raw == "" || !strings.HasPrefix(raw, "{") {
+		return nil, errors.New("no JSON object found in response")
+	}
+
+	var outline entity.Outline
+	if err := json.Unmarshal([]byte(raw), &outline); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	known := make(map[string]bool, len(trendIDs))
+	for _, id := range trendIDs {
+		known[id] = true
+	}
+
+	if strings.TrimSpace(outline.Title) == "" {
+		return nil, errors.New("title is empty")
+	}
+	if len(outline.Sections) == 0 {
+		return nil, errors.New("sections is empty")
+	}
+	for i, s := range outline.Sections {
+		if strings.TrimSpace(s.Heading) == "" {
+			return nil, fmt.Errorf("sections[%d].heading is empty", i)
+		}
+		for _, id := range s.Sources {
+			if len(known) > 0 && !known[id] {
+				return nil, fmt.Errorf("sections[%d].sources contains unknown trend ID %q", i, id)
+			}
+		}
+	}
+	return &outline, nil
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how often a failed call is repeated. Only rate limits
+// and provider outages are retried.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than this is not
+	// waited for; the error is returned instead.
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// sleep waits for d or until ctx is done; replaced in tests.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type retryProvider struct {
+	Provider
+	policy RetryPolicy
+}
+
+// WithRetry wraps p so retryable errors are repeated with exponential
+// backoff and jitter, honouring Retry-After.
+func WithRetry(p Provider, policy RetryPolicy) Provider {
+	if policy.MaxAttempts <= 1 {
+		return p
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	return &retryProvider{Provider: p, policy: policy}
+}
+
+func (r *retryProvider) Chat(ctx context.Context, req Request) (*Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := r.Provider.Chat(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt+1 >= r.policy.MaxAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait := apiErr.RetryAfter
+		if wait == 0 {
+			wait = r.backoff(attempt)
+		}
+		if wait > r.policy.MaxDelay {
+			return nil, err
+		}
+
+		slog.WarnContext(ctx, "llm call failed, retrying",
+			"provider", r.Name(), "operation", req.Operation, "attempt", attempt+1, "wait", wait, "error", err)
+		if serr := sleep(ctx, wait); serr != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns a delay in [d/2, d] where d doubles with every attempt.
+func (r *retryProvider) backoff(attempt int) time.Duration {
+	d := r.policy.BaseDelay << attempt
+	if d > r.policy.MaxDelay || d <= 0 {
+		d = r.policy.MaxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Unwrap returns the provider that is retried.
+func (r *retryProvider) Unwrap() Provider {
+	return r.Provider
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/domain"
+)
+
+type flakyProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) Name() string  { return "flaky" }
+func (p *flakyProvider) Model() string { return "test" }
+
+func (p *flakyProvider) Chat(ctx context.Context, req Request) (*Response, error) {
+	p.calls++
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return nil, err
+	}
+	return &Response{Content: "ok"}, nil
+}
+
+func TestWithRetry(t *testing.T) {
+	var waits []time.Duration
+	orig := sleep
+	t.Cleanup(func() { sleep = orig })
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	rateLimited := &APIError{Provider: "flaky", Status: 429, RetryAfter: 7 * time.Second, Kind: domain.ErrLLMRateLimited}
+	outage := &APIError{Provider: "flaky", Status: 503, Kind: domain.ErrLLMUnavailable}
+	auth := &APIError{Provider: "flaky", Status: 401, Kind: domain.ErrLLMAuth}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+		wantWaits []time.Duration
+	}{
+		{name: "honours retry-after", errs: []error{rateLimited}, wantCalls: 2, wantWaits: []time.Duration{7 * time.Second}},
+		{name: "gives up after max attempts", errs: []error{outage, outage, outage}, wantErr: domain.ErrLLMUnavailable, wantCalls: 3},
+		{name: "does not retry auth errors", errs: []error{auth}, wantErr: domain.ErrLLMAuth, wantCalls: 1},
+		{
+			name:      "does not wait longer than max delay",
+			errs:      []error{&APIError{Status: 429, RetryAfter: time.Minute, Kind: domain.ErrLLMRateLimited}},
+			wantErr:   domain.ErrLLMRateLimited,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits = nil
+			p := &flakyProvider{errs: tt.errs}
+
+			_, err := WithRetry(p, policy).Chat(context.Background(), Request{})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if p.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", p.calls, tt.wantCalls)
+			}
+			if tt.wantWaits != nil && (len(waits) != len(tt.wantWaits) || waits[0] != tt.wantWaits[0]) {
+				t.Errorf("waits = %v, want %v", waits, tt.wantWaits)
+			}
+			for _, w := range waits {
+				if w > policy.MaxDelay {
+					t.Errorf("waited %v, more than max delay", w)
+				}
+			}
+		})
+	}
+}
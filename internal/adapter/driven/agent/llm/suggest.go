@@ -0,0 +1,187 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+)
+
+// MaxRepairs is how often an invalid suggestion response is sent back to the
+// model with the validation error before giving up.
+const MaxRepairs = 1
+
+// SuggestionSchema is the JSON schema suggestions are requested with. It
+// follows the strict structured-output rules (every property required, no
+// additional properties), so it can be passed to OpenAI as is.
+var SuggestionSchema = Schema{
+	Name: "topic_suggestions",
+	Schema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "suggestions": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "title": {"type": "string"},
+          "description": {"type": "string"},
+          "score": {"type": "number"},
+          "topics": {"type": "array", "items": {"type": "string"}}
+        },
+        "required": ["title", "description", "score", "topics"],
+        "additionalProperties": false
+      }
+    }
+  },
+  "required": ["suggestions"],
+  "additionalProperties": false
+}`),
+}
+
+func (a *Agent) Suggest(ctx context.Context, req service.SuggestRequest) ([]service.TopicSuggestion, error) {
+	var suggestions []service.TopicSuggestion
+	err := a.structured(ctx, OperationSuggest, req.Prompt, suggestInstructions(req.TrendIDs), &SuggestionSchema, func(response string) error {
+		var err error
+		suggestions, err = parseSuggestions(response, req.TrendIDs)
+		return err
+	})
+	return suggestions, err
+}
+
+// structured asks for JSON matching schema and hands the response to parse.
+// When parse rejects it, the error is sent back to the model up to
+// MaxRepairs times before failing with domain.ErrLLMInvalidOutput.
+func (a *Agent) structured(ctx context.Context, operation, prompt, instructions string, schema *Schema, parse func(string) error) error {
+	messages := []Message{
+		{Role: "system", Content: instructions},
+		{Role: "user", Content: prompt},
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := a.chat(ctx, Request{
+			Operation: operation,
+			Messages:  messages,
+			Schema:    schema,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = parse(response)
+		if err == nil {
+			return nil
+		}
+		if attempt >= MaxRepairs {
+			return fmt.Errorf("%w: %v", domain.ErrLLMInvalidOutput, err)
+		}
+
+		messages = append(messages,
+			Message{Role: "assistant", Content: response},
+			Message{Role: "user", Content: fmt.Sprintf(
+				"That response is invalid: %v.\nReply again with only the JSON object, following the schema exactly.", err)},
+		)
+	}
+}
+
+func schemaInstructions(schema *Schema) string {
+	return "Respond with a single JSON object and nothing else, matching this JSON schema:\n" + string(schema.Schema)
+}
+
+func suggestInstructions(trendIDs []string) string {
+	var b strings.Builder
+	b.WriteString(schemaInstructions(&SuggestionSchema))
+	b.WriteString("\n\nscore is your confidence between 0 and 1.")
+	if len(trendIDs) > 0 {
+		b.WriteString(" topics lists the IDs of the trends each suggestion is based on, chosen from: ")
+		b.WriteString(strings.Join(trendIDs, ", "))
+		b.WriteString(".")
+	}
+	return b.String()
+}
+
+// parseSuggestions decodes and validates a suggestion response. Code fences
+// and surrounding prose are tolerated, as is a bare array instead of the
+// {"suggestions": [...]} object.
+func parseSuggestions(response string, trendIDs []string) ([]service.TopicSuggestion, error) {
+	raw := extractJSON(response)
+	if raw == "" {
+		return nil, errors.New("no JSON found in response")
+	}
+
+	var suggestions []service.TopicSuggestion
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &suggestions); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else {
+		var wrapper struct {
+			Suggestions []service.TopicSuggestion `json:"suggestions"`
+		}
+		if err := json.Unmarshal([]byte(raw), &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		suggestions = wrapper.Suggestions
+	}
+
+	if err := validateSuggestions(suggestions, trendIDs); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+func validateSuggestions(suggestions []service.TopicSuggestion, trendIDs []string) error {
+	if len(suggestions) == 0 {
+		return errors.New("suggestions is empty")
+	}
+
+	known := make(map[string]bool, len(trendIDs))
+	for _, id := range trendIDs {
+		known[id] = true
+	}
+
+	for i, s := range suggestions {
+		switch {
+		case strings.TrimSpace(s.Title) == "":
+			return fmt.Errorf("suggestions[%d].title is empty", i)
+		case strings.TrimSpace(s.Description) == "":
+			return fmt.Errorf("suggestions[%d].description is empty", i)
+		case s.Score < 0 || s.Score > 1:
+			return fmt.Errorf("suggestions[%d].score %v is not between 0 and 1", i, s.Score)
+		}
+
+		if len(trendIDs) == 0 {
+			continue
+		}
+		if len(s.Topics) == 0 {
+			return fmt.Errorf("suggestions[%d].topics must list at least one trend ID", i)
+		}
+		for _, id := range s.Topics {
+			if !known[id] {
+				return fmt.Errorf("suggestions[%d].topics contains unknown trend ID %q", i, id)
+			}
+		}
+	}
+	return nil
+}
+
+// extractJSON returns the outermost JSON object or array in text.
+func extractJSON(text string) string {
+	start := strings.IndexAny(text, "{[")
+	if start == -1 {
+		return ""
+	}
+	closer := "}"
+	if text[start] == '[' {
+		closer = "]"
+	}
+	end := strings.LastIndex(text, closer)
+	if end < start {
+		return ""
+	}
+	return text[start : end+1]
+}
@@ -0,0 +1,157 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+)
+
+const (
+	DefaultBaseURL = "http://localhost:11434"
+	DefaultModel   = "llama3.2"
+)
+
+// Provider talks to a local Ollama server using its native /api/chat
+// endpoint. No API key is needed.
+type Provider struct {
+	baseURL     string
+	model       string
+	temperature *float64
+	maxTokens   int
+	client      *http.Client
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []llm.Message `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  *chatOptions  `json:"options,omitempty"`
+	// Format constrains the output to a JSON schema.
+	Format json.RawMessage `json:"format,omitempty"`
+}
+
+type chatOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+}
+
+type chatResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error,omitempty"`
+}
+
+func New(cfg llm.Config) (*Provider, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+
+	return &Provider{
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+		maxTokens:   cfg.MaxTokens,
+		client: &http.Client{
+			Timeout: cfg.TimeoutOrDefault(),
+		},
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return "ollama"
+}
+
+func (p *Provider) Model() string {
+	return p.model
+}
+
+// Ping lists the local models, which checks that Ollama is running.
+func (p *Provider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/tags", nil)
+	if err != nil {
+		return err
+	}
+	return llm.Ping(p.client, "ollama", req)
+}
+
+func (p *Provider) Chat(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	chatReq := chatRequest{
+		Model:    p.model,
+		Messages: req.Messages,
+	}
+	if req.Schema != nil {
+		chatReq.Format = req.Schema.Schema
+	}
+	if p.temperature != nil || p.maxTokens > 0 {
+		chatReq.Options = &chatOptions{
+			Temperature: p.temperature,
+			NumPredict:  p.maxTokens,
+		}
+	}
+
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, llm.NewTransportError("ollama", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, llm.NewTransportError("ollama", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, llm.NewHTTPError("ollama", resp, respBody)
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, llm.NewDecodeError("ollama", resp, respBody, err)
+	}
+
+	if chatResp.Error != "" {
+		return nil, &llm.APIError{
+			Provider: "ollama",
+			Status:   resp.StatusCode,
+			Message:  chatResp.Error,
+			Kind:     llm.Classify(resp.StatusCode, chatResp.Error),
+		}
+	}
+
+	if chatResp.Message.Content == "" {
+		return nil, llm.NewEmptyResponseError("ollama", resp)
+	}
+
+	return &llm.Response{
+		Content: chatResp.Message.Content,
+		Usage: llm.Usage{
+			PromptTokens:     chatResp.PromptEvalCount,
+			CompletionTokens: chatResp.EvalCount,
+		},
+	}, nil
+}
@@ -0,0 +1,138 @@
+package ollama_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/agent/ollama"
+	"r3f-trends/internal/domain"
+)
+
+func newProvider(t *testing.T, cfg llm.Config, handler http.HandlerFunc) *ollama.Provider {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg.BaseURL = srv.URL
+	provider, err := ollama.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return provider
+}
+
+func TestProviderChat(t *testing.T) {
+	var got struct {
+		Model    string          `json:"model"`
+		Messages []llm.Message   `json:"messages"`
+		Stream   bool            `json:"stream"`
+		Format   json.RawMessage `json:"format"`
+		Options  struct {
+			Temperature *float64 `json:"temperature"`
+			NumPredict  int      `json:"num_predict"`
+		} `json:"options"`
+	}
+	temperature := 0.2
+	provider := newProvider(t, llm.Config{Temperature: &temperature, MaxTokens: 256}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"message":{"role":"assistant","content":"{\"suggestions\":[]}"},"prompt_eval_count":40,"eval_count":9}`))
+	})
+
+	resp, err := provider.Chat(context.Background(), llm.Request{
+		Messages: []llm.Message{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Suggest"}},
+		Schema:   &llm.SuggestionSchema,
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Content != `{"suggestions":[]}` {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	if resp.Usage != (llm.Usage{PromptTokens: 40, CompletionTokens: 9}) {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+
+	if got.Model != ollama.DefaultModel || got.Stream || len(got.Messages) != 2 {
+		t.Errorf("request = %+v", got)
+	}
+	var want bytes.Buffer
+	json.Compact(&want, llm.SuggestionSchema.Schema)
+	if string(got.Format) != want.String() {
+		t.Errorf("format = %s, want the suggestion schema", got.Format)
+	}
+	if got.Options.Temperature == nil || *got.Options.Temperature != 0.2 || got.Options.NumPredict != 256 {
+		t.Errorf("options = %+v", got.Options)
+	}
+}
+
+func TestProviderChatTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"server error", http.StatusInternalServerError, `{"error":"llama runner process has terminated"}`, domain.ErrLLMUnavailable},
+		{"context length", http.StatusBadRequest, `{"error":"input exceeds the context window"}`, domain.ErrLLMContextLength},
+		{"empty message", http.StatusOK, `{"message":{"role":"assistant","content":""}}`, domain.ErrLLMUnavailable},
+		{"html page with 200", http.StatusOK, `<html><body>Please log in to the proxy</body></html>`, domain.ErrLLMUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newProvider(t, llm.Config{}, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := provider.Chat(context.Background(), llm.Request{
+				Messages: []llm.Message{{Role: "user", Content: "hi"}},
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderChatUnknownModel(t *testing.T) {
+	provider := newProvider(t, llm.Config{Model: "nope"}, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model \"nope\" not found, try pulling it first"}`))
+	})
+
+	_, err := provider.Chat(context.Background(), llm.Request{Messages: []llm.Message{{Role: "user", Content: "hi"}}})
+	var apiErr *llm.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound || apiErr.Retryable() {
+		t.Errorf("err = %v, want a non-retryable 404 APIError", err)
+	}
+}
+
+func TestProviderPing(t *testing.T) {
+	provider := newProvider(t, llm.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/tags" {
+			t.Errorf("ping request = %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"models":[]}`))
+	})
+	if err := provider.Ping(context.Background()); err != nil {
+		t.Errorf("Ping = %v, want nil", err)
+	}
+
+	// Nothing listens on the closed server's address.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	down, _ := ollama.New(llm.Config{BaseURL: srv.URL})
+	if err := down.Ping(context.Background()); !errors.Is(err, domain.ErrLLMUnavailable) {
+		t.Errorf("Ping without a server = %v, want ErrLLMUnavailable", err)
+	}
+}
@@ -0,0 +1,205 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/domain"
+)
+
+const (
+	DefaultBaseURL = "https://api.openai.com/v1"
+	DefaultModel   = "gpt-4o-mini"
+
+	FormatJSONSchema = "json_schema"
+	FormatJSONObject = "json_object"
+	FormatNone       = "none"
+)
+
+// Provider talks to any endpoint implementing the OpenAI chat completions
+// API (OpenAI, z.ai, OpenRouter, vLLM, LM Studio, ...).
+type Provider struct {
+	name        string
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature *float64
+	maxTokens   int
+	format      string
+	client      *http.Client
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []llm.Message   `json:"messages"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+	Strict bool            `json:"strict"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func New(cfg llm.Config) (*Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("%s: %w", cfg.Provider, llm.ErrMissingAPIKey)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = "openai"
+	}
+	switch cfg.ResponseFormat {
+	case "":
+		cfg.ResponseFormat = FormatJSONSchema
+	case FormatJSONSchema, FormatJSONObject, FormatNone:
+	default:
+		return nil, fmt.Errorf("%w: unknown llm.response_format %q", domain.ErrInvalidConfig, cfg.ResponseFormat)
+	}
+
+	return &Provider{
+		name:        cfg.Provider,
+		apiKey:      cfg.APIKey,
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+		maxTokens:   cfg.MaxTokens,
+		format:      cfg.ResponseFormat,
+		client: &http.Client{
+			Timeout: cfg.TimeoutOrDefault(),
+		},
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) Model() string {
+	return p.model
+}
+
+// Ping lists the models, which checks the base URL and the API key without
+// spending tokens.
+func (p *Provider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/models", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	return llm.Ping(p.client, p.name, req)
+}
+
+func (p *Provider) responseFormat(schema *llm.Schema) *responseFormat {
+	if schema == nil {
+		return nil
+	}
+	switch p.format {
+	case FormatJSONSchema:
+		return &responseFormat{
+			Type:       FormatJSONSchema,
+			JSONSchema: &jsonSchema{Name: schema.Name, Schema: schema.Schema, Strict: true},
+		}
+	case FormatJSONObject:
+		return &responseFormat{Type: FormatJSONObject}
+	}
+	return nil
+}
+
+func (p *Provider) Chat(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:          p.model,
+		Messages:       req.Messages,
+		Temperature:    p.temperature,
+		MaxTokens:      p.maxTokens,
+		ResponseFormat: p.responseFormat(req.Schema),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, llm.NewTransportError(p.name, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, llm.NewTransportError(p.name, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, llm.NewHTTPError(p.name, resp, respBody)
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, llm.NewDecodeError(p.name, resp, respBody, err)
+	}
+
+	if chatResp.Error != nil {
+		return nil, &llm.APIError{
+			Provider: p.name,
+			Status:   resp.StatusCode,
+			Message:  chatResp.Error.Message,
+			Kind:     llm.Classify(resp.StatusCode, chatResp.Error.Message),
+		}
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, llm.NewEmptyResponseError(p.name, resp)
+	}
+
+	return &llm.Response{
+		Content: chatResp.Choices[0].Message.Content,
+		Usage: llm.Usage{
+			PromptTokens:     chatResp.Usage.PromptTokens,
+			CompletionTokens: chatResp.Usage.CompletionTokens,
+		},
+	}, nil
+}
@@ -0,0 +1,221 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/agent/openai"
+	"r3f-trends/internal/app/prompt"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+)
+
+func TestProviderChat(t *testing.T) {
+	srv := fake.NewServer(fake.New().On("Summarize", "A short summary."))
+	defer srv.Close()
+
+	provider, err := openai.New(llm.Config{
+		APIKey:  "test",
+		BaseURL: srv.URL,
+		Model:   "test-model",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := provider.Chat(context.Background(), llm.Request{
+		Messages: []llm.Message{{Role: "user", Content: "Summarize this"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Content != "A short summary." {
+		t.Errorf("Content = %q", resp.Content)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	if reqs[0].Model != "test-model" {
+		t.Errorf("Model = %q, want test-model", reqs[0].Model)
+	}
+}
+
+// TestAgentThroughFakeServer runs every structured operation through the
+// stand-in, which has to answer each with the right canned shape.
+func TestAgentThroughFakeServer(t *testing.T) {
+	ctx := context.Background()
+	srv := fake.NewServer(nil)
+	defer srv.Close()
+
+	for _, format := range []string{openai.FormatJSONSchema, openai.FormatJSONObject} {
+		provider, err := openai.New(llm.Config{APIKey: "test", BaseURL: srv.URL, ResponseFormat: format})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		agent := llm.NewAgent(provider)
+
+		suggestions, err := agent.Suggest(ctx, service.SuggestRequest{
+			Prompt:   "Topics:\n- [hn-1] Go 1.26 released\n",
+			TrendIDs: []string{"hn-1"},
+		})
+		if err != nil || len(suggestions) != 1 || suggestions[0].Topics[0] != "hn-1" {
+			t.Errorf("%s: Suggest = %+v, %v", format, suggestions, err)
+		}
+
+		outline, err := agent.Outline(ctx, service.OutlineRequest{
+			Prompt:   "Sources:\n[hn-1] Go 1.26 released\n",
+			TrendIDs: []string{"hn-1"},
+		})
+		if err != nil || len(outline.Sections) != 1 {
+			t.Errorf("%s: Outline = %+v, %v", format, outline, err)
+		}
+
+		tags, err := agent.Tag(ctx, prompt.DefaultTagger)
+		if err != nil || tags.Category == "" {
+			t.Errorf("%s: Tag = %+v, %v", format, tags, err)
+		}
+
+		draft, err := agent.Draft(ctx, "Write a blog post following this outline:\n\n# Go 1.26\n## What changed\n")
+		if err != nil || !strings.HasPrefix(draft, "# Go 1.26\n") {
+			t.Errorf("%s: Draft = %q, %v", format, draft, err)
+		}
+	}
+}
+
+func TestProviderChatAPIError(t *testing.T) {
+	p := fake.New()
+	p.Rules(fake.Rule{Match: "boom", Error: "model overloaded"})
+	srv := fake.NewServer(p)
+	defer srv.Close()
+
+	provider, err := openai.New(llm.Config{APIKey: "test", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = provider.Chat(context.Background(), llm.Request{
+		Messages: []llm.Message{{Role: "user", Content: "boom"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "model overloaded") {
+		t.Fatalf("Chat error = %v, want API error", err)
+	}
+}
+
+func TestProviderChatTypedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    error
+	}{
+		{
+			name: "auth",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+			},
+			want: domain.ErrLLMAuth,
+		},
+		{
+			name: "rate limit",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				http.Error(w, `{"error":{"message":"slow down"}}`, http.StatusTooManyRequests)
+			},
+			want: domain.ErrLLMRateLimited,
+		},
+		{
+			name: "context length",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"error":{"message":"This model's maximum context length is 8192 tokens"}}`, http.StatusBadRequest)
+			},
+			want: domain.ErrLLMContextLength,
+		},
+		{
+			name: "html outage page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "<html><body>502 Bad Gateway</body></html>", http.StatusBadGateway)
+			},
+			want: domain.ErrLLMUnavailable,
+		},
+		{
+			name: "no choices",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"choices":[]}`))
+			},
+			want: domain.ErrLLMUnavailable,
+		},
+		{
+			name: "html page with 200",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("<html><body>Please log in to the proxy</body></html>"))
+			},
+			want: domain.ErrLLMUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			provider, err := openai.New(llm.Config{APIKey: "test", BaseURL: srv.URL})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			_, err = provider.Chat(context.Background(), llm.Request{
+				Messages: []llm.Message{{Role: "user", Content: "hi"}},
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+
+			var apiErr *llm.APIError
+			if errors.As(err, &apiErr) && tt.want == domain.ErrLLMRateLimited && apiErr.RetryAfter != 7*time.Second {
+				t.Errorf("RetryAfter = %v, want 7s", apiErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestAgentPing(t *testing.T) {
+	ctx := context.Background()
+	srv := fake.NewServer(nil)
+	defer srv.Close()
+	provider, err := openai.New(llm.Config{APIKey: "test", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := llm.NewAgent(llm.WithRetry(provider, llm.DefaultRetryPolicy)).Ping(ctx); err != nil {
+		t.Errorf("Ping = %v, want nil", err)
+	}
+
+	for status, want := range map[int]error{
+		http.StatusUnauthorized: domain.ErrLLMAuth,
+		http.StatusNotFound:     nil,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error":{"message":"nope"}}`, status)
+		}))
+		provider, _ := openai.New(llm.Config{APIKey: "bad", BaseURL: srv.URL})
+		err := llm.NewAgent(provider).Ping(ctx)
+		if err == nil || (want != nil && !errors.Is(err, want)) {
+			t.Errorf("Ping with HTTP %d = %v, want an error wrapping %v", status, err, want)
+		}
+		srv.Close()
+	}
+}
+
+func TestNewRequiresAPIKey(t *testing.T) {
+	if _, err := openai.New(llm.Config{}); err == nil {
+		t.Fatal("expected error without api key")
+	}
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"r3f-trends/internal/adapter/driven/agent/anthropic"
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/glm5"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/agent/ollama"
+	"r3f-trends/internal/adapter/driven/agent/openai"
+	"r3f-trends/internal/domain"
+)
+
+// DefaultProvider is used when llm.provider is empty.
+const DefaultProvider = "zai"
+
+type Factory func(cfg llm.Config) (llm.Provider, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{
+		"zai": func(cfg llm.Config) (llm.Provider, error) {
+			return glm5.New(cfg)
+		},
+		"openai": func(cfg llm.Config) (llm.Provider, error) {
+			return openai.New(cfg)
+		},
+		"ollama": func(cfg llm.Config) (llm.Provider, error) {
+			return ollama.New(cfg)
+		},
+		"anthropic": func(cfg llm.Config) (llm.Provider, error) {
+			return anthropic.New(cfg)
+		},
+		"fake": func(cfg llm.Config) (llm.Provider, error) {
+			return fake.FromConfig(cfg)
+		},
+	}
+)
+
+// Register adds or replaces the factory for a provider name.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[strings.ToLower(name)] = factory
+}
+
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds the agent for cfg.Provider.
+func New(cfg llm.Config, opts ...llm.AgentOption) (*llm.Agent, error) {
+	if cfg.Provider == "" {
+		cfg.Provider = DefaultProvider
+	}
+	cfg.Provider = strings.ToLower(cfg.Provider)
+
+	mu.RLock()
+	factory, ok := factories[cfg.Provider]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown llm provider %q (available: %s)",
+			domain.ErrInvalidConfig, cfg.Provider, strings.Join(Providers(), ", "))
+	}
+
+	provider, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := cfg.Retry
+	if policy.MaxAttempts == 0 {
+		policy = llm.DefaultRetryPolicy
+	}
+
+	return llm.NewAgent(llm.WithRetry(provider, policy), opts...), nil
+}
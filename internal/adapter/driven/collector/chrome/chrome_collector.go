@@ -3,6 +3,7 @@ package chrome
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -13,18 +14,60 @@ import (
 )
 
 type ChromeCollector struct {
-	mu       sync.Mutex
-	timeout  time.Duration
-	headless bool
+	mu          sync.Mutex
+	timeout     time.Duration
+	headless    bool
+	now         func() time.Time
+	rewriteURL  func(string) string
+	recordPage  func(url, html string) error
+	settleDelay time.Duration
 }
 
-func New() *ChromeCollector {
-	return &ChromeCollector{
-		timeout:  30 * time.Second,
-		headless: true,
+type Option func(*ChromeCollector)
+
+// WithURLRewriter changes the URL that is actually loaded, e.g. to point at
+// a local fixture server. Trends still report the source's configured URL.
+func WithURLRewriter(rewrite func(string) string) Option {
+	return func(c *ChromeCollector) {
+		c.rewriteURL = rewrite
+	}
+}
+
+// WithSettleDelay sets how long to wait after the page loads before reading
+// it, so client-side rendering can finish. Snapshots served locally are
+// already complete and can use 0.
+func WithSettleDelay(d time.Duration) Option {
+	return func(c *ChromeCollector) {
+		c.settleDelay = d
 	}
 }
 
+// WithPageRecorder is called with the rendered HTML of every collected page.
+func WithPageRecorder(record func(url, html string) error) Option {
+	return func(c *ChromeCollector) {
+		c.recordPage = record
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(c *ChromeCollector) {
+		c.now = now
+	}
+}
+
+func New(opts ...Option) *ChromeCollector {
+	c := &ChromeCollector{
+		timeout:     30 * time.Second,
+		headless:    true,
+		now:         time.Now,
+		settleDelay: 2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
 func (c *ChromeCollector) Type() valueobject.CollectorType {
 	return valueobject.CollectorTypeChrome
 }
@@ -40,6 +83,9 @@ func (c *ChromeCollector) Validate(source *entity.Source) error {
 func (c *ChromeCollector) Test(ctx context.Context, source *entity.Source) error {
 	cfg := source.Config()
 	url, _ := cfg["url"].(string)
+	if c.rewriteURL != nil {
+		url = c.rewriteURL(url)
+	}
 
 	allocCtx, cancel := c.createContext(ctx)
 	defer cancel()
@@ -52,6 +98,14 @@ func (c *ChromeCollector) Test(ctx context.Context, source *entity.Source) error
 	)
 }
 
+// CheckLaunch starts the browser on a blank page, to tell whether Chrome
+// sources can be collected at all.
+func (c *ChromeCollector) CheckLaunch(ctx context.Context) error {
+	allocCtx, cancel := c.createContext(ctx)
+	defer cancel()
+	return chromedp.Run(allocCtx, chromedp.Navigate("about:blank"))
+}
+
 func (c *ChromeCollector) Collect(ctx context.Context, source *entity.Source) ([]*entity.Trend, error) {
 	cfg := source.Config()
 
@@ -62,24 +116,42 @@ func (c *ChromeCollector) Collect(ctx context.Context, source *entity.Source) ([
 	linkSel, _ := cfg["link_selector"].(string)
 	descSel, _ := cfg["description_selector"].(string)
 
+	pageURL := url
+	if c.rewriteURL != nil {
+		pageURL = c.rewriteURL(url)
+	}
+
 	allocCtx, cancel := c.createContext(ctx)
 	defer cancel()
 
 	var results []map[string]string
+	var html string
 
 	jsScript := c.buildExtractionScript(containerSel, titleSel, linkSel, descSel)
 
-	err := chromedp.Run(allocCtx,
-		chromedp.Navigate(url),
-		chromedp.Sleep(2*time.Second),
+	actions := []chromedp.Action{
+		chromedp.Navigate(pageURL),
+		chromedp.Sleep(c.settleDelay),
 		c.waitForElement(waitSelector),
 		chromedp.Evaluate(jsScript, &results),
-	)
+	}
+	if c.recordPage != nil {
+		actions = append(actions, chromedp.OuterHTML("html", &html, chromedp.ByQuery))
+	}
+
+	slog.DebugContext(ctx, "loading page", "url", pageURL)
+	err := chromedp.Run(allocCtx, actions...)
 
 	if err != nil {
 		return nil, fmt.Errorf("chrome collection failed: %w", err)
 	}
 
+	if c.recordPage != nil {
+		if err := c.recordPage(url, html); err != nil {
+			return nil, fmt.Errorf("record page: %w", err)
+		}
+	}
+
 	trends := make([]*entity.Trend, 0, len(results))
 	fieldMapping := source.FieldMapping()
 
@@ -89,6 +161,9 @@ func (c *ChromeCollector) Collect(ctx context.Context, source *entity.Source) ([
 			trends = append(trends, trend)
 		}
 	}
+	if skipped := len(results) - len(trends); skipped > 0 {
+		slog.WarnContext(ctx, "page items without a title skipped", "url", pageURL, "skipped", skipped)
+	}
 
 	return trends, nil
 }
@@ -177,7 +252,7 @@ func (c *ChromeCollector) mapToTrend(item map[string]string, source *entity.Sour
 		return nil
 	}
 
-	id := fmt.Sprintf("%s-%d-%d", source.ID(), time.Now().Unix(), index)
+	id := fmt.Sprintf("%s-%d-%d", source.ID(), c.now().Unix(), index)
 	url := getField("url")
 	if url == "" {
 		url = getField("link")
@@ -0,0 +1,77 @@
+package chrome_test
+
+import (
+	"context"
+	"flag"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	chromecollector "r3f-trends/internal/adapter/driven/collector/chrome"
+	"r3f-trends/internal/adapter/driven/collector/fixture"
+	"r3f-trends/internal/adapter/driven/collector/fixture/fixturetest"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+)
+
+var (
+	update = flag.Bool("update", false, "rewrite golden files")
+	record = flag.Bool("record", false, "re-record page snapshots from the live sources")
+)
+
+const (
+	sourcesDir  = "../../../../../config/sources"
+	fixturesDir = "testdata/fixtures"
+	goldenDir   = "testdata/golden"
+)
+
+var collectedAt = time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+func requireChrome(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "headless-shell"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return
+		}
+	}
+	t.Skip("chrome not installed")
+}
+
+func TestCollectGolden(t *testing.T) {
+	requireChrome(t)
+
+	sources, err := yaml.NewSourceLoader(sourcesDir).LoadAll(context.Background())
+	if err != nil {
+		t.Fatalf("load sources: %v", err)
+	}
+
+	pages := fixture.NewStaticServer(fixturesDir)
+	defer pages.Close()
+
+	collector := chromecollector.New(
+		chromecollector.WithURLRewriter(pages.Rewrite),
+		chromecollector.WithSettleDelay(0),
+		chromecollector.WithClock(func() time.Time { return collectedAt }),
+	)
+	if *record {
+		collector = chromecollector.New(
+			chromecollector.WithPageRecorder(fixture.PageRecorder(fixturesDir)),
+			chromecollector.WithClock(func() time.Time { return collectedAt }),
+		)
+	}
+
+	for _, source := range sources {
+		if source.Type() != "chrome" {
+			continue
+		}
+
+		t.Run(source.ID(), func(t *testing.T) {
+			trends, err := collector.Collect(context.Background(), source)
+			if err != nil {
+				t.Fatalf("Collect: %v", err)
+			}
+
+			fixturetest.AssertGolden(t, filepath.Join(goldenDir, source.ID()+".json"), trends, *update || *record)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Mode string
+
+const (
+	ModeOff    Mode = ""
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+var ErrNotRecorded = errors.New("no fixture recorded for request")
+
+// Response is the on-disk form of a recorded HTTP exchange. JSON bodies are
+// stored inline under "json" so fixtures stay readable and diffable; any
+// other body is stored as text under "body".
+type Response struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   string          `json:"body,omitempty"`
+}
+
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case ModeOff, ModeRecord, ModeReplay:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown fixture mode %q", s)
+	}
+}
+
+// Name maps a request to a stable, filesystem-safe fixture name.
+func Name(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	key := rawURL
+	if err == nil {
+		key = u.Host + u.Path
+		if u.RawQuery != "" {
+			key += "?" + u.RawQuery
+		}
+	}
+	if method != "" && method != http.MethodGet {
+		key = method + " " + key
+	}
+
+	var b strings.Builder
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+
+	if len(name) > 120 {
+		sum := sha1.Sum([]byte(key))
+		name = name[:100] + "-" + hex.EncodeToString(sum[:])[:12]
+	}
+	return name
+}
+
+// Recorder is an http.RoundTripper that forwards requests to Next and writes
+// every response to Dir.
+type Recorder struct {
+	Dir  string
+	Next http.RoundTripper
+
+	mu sync.Mutex
+}
+
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{Dir: dir, Next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := Response{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: http.Header{},
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		rec.Header.Set("Content-Type", ct)
+	}
+	if json.Valid(body) {
+		var compact bytes.Buffer
+		json.Compact(&compact, body)
+		rec.JSON = compact.Bytes()
+	} else {
+		rec.Body = string(body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := Save(r.Dir, rec); err != nil {
+		return nil, fmt.Errorf("record fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers from fixtures in Dir and
+// never touches the network.
+type Replayer struct {
+	Dir string
+}
+
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{Dir: dir}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec, err := Load(r.Dir, req.Method, req.URL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte(rec.Body)
+	if len(rec.JSON) > 0 {
+		body = rec.JSON
+	}
+
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func Save(dir string, rec Response) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file(dir, rec.Method, rec.URL), append(data, '\n'), 0644)
+}
+
+func Load(dir, method, rawURL string) (*Response, error) {
+	data, err := os.ReadFile(file(dir, method, rawURL))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, method, rawURL)
+		}
+		return nil, err
+	}
+
+	var rec Response
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid fixture for %s: %w", rawURL, err)
+	}
+	return &rec, nil
+}
+
+// file is where the fixture for a request is stored. URLs that already end in
+// .json keep a single extension.
+func file(dir, method, rawURL string) string {
+	return filepath.Join(dir, strings.TrimSuffix(Name(method, rawURL), ".json")+".json")
+}
@@ -0,0 +1,86 @@
+package fixture_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/collector/fixture"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ids.json":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, "[1, 2, 3]")
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, "<html>page</html>")
+		}
+	}))
+	defer live.Close()
+
+	dir := t.TempDir()
+	recording := &http.Client{Transport: fixture.NewRecorder(dir, nil)}
+	for _, path := range []string{"/ids.json", "/page?x=1"} {
+		resp, err := recording.Get(live.URL + path)
+		if err != nil {
+			t.Fatalf("record %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+	live.Close()
+
+	if names, _ := filepath.Glob(filepath.Join(dir, "*.json.json")); len(names) > 0 {
+		t.Errorf("fixtures with a doubled extension: %v", names)
+	}
+
+	replaying := &http.Client{Transport: fixture.NewReplayer(dir)}
+	tests := map[string]string{
+		"/ids.json": "[1,2,3]",
+		"/page?x=1": "<html>page</html>",
+	}
+	for path, want := range tests {
+		resp, err := replaying.Get(live.URL + path)
+		if err != nil {
+			t.Fatalf("replay %s: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		// JSON bodies are re-indented on disk, so compare without whitespace.
+		got := strings.Join(strings.Fields(string(body)), "")
+		if resp.StatusCode != http.StatusOK || got != strings.Join(strings.Fields(want), "") {
+			t.Errorf("replay %s = %d %q, want 200 %q", path, resp.StatusCode, body, want)
+		}
+	}
+
+	if _, err := replaying.Get(live.URL + "/unknown"); err == nil || !strings.Contains(err.Error(), "no fixture recorded") {
+		t.Errorf("unrecorded request error = %v", err)
+	}
+}
+
+func TestStaticServerResolvesLinksAgainstOrigin(t *testing.T) {
+	dir := t.TempDir()
+	if err := fixture.SavePage(dir, "https://github.com/trending/go", "<html><HEAD lang=\"en\"></HEAD><body><a href=\"/golang/go\">go</a></body></html>"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := fixture.NewStaticServer(dir)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.Rewrite("https://github.com/trending/go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if !strings.Contains(string(body), `<HEAD lang="en"><base href="https://github.com/trending/go">`) {
+		t.Errorf("base tag not injected: %s", body)
+	}
+}
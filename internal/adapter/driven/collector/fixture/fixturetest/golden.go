@@ -0,0 +1,54 @@
+// Package fixturetest holds helpers for collector tests that replay
+// fixtures. Only tests import it, so production binaries do not link
+// package testing.
+package fixturetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// AssertGolden compares trends with the golden file at path after clearing
+// fields that depend on when the test runs. With update set it rewrites the
+// file instead.
+func AssertGolden(t testing.TB, path string, trends []*entity.Trend, update bool) {
+	t.Helper()
+
+	dtos := make([]*entity.TrendDTO, len(trends))
+	for i, trend := range trends {
+		dto := trend.ToDTO()
+		dto.CollectedAt = time.Time{}
+		dto.Timestamp = dto.Timestamp.UTC()
+		dtos[i] = dto
+	}
+
+	got, err := json.MarshalIndent(dtos, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got = append(got, '\n')
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch (run with -update to accept):\n got: %s\nwant: %s", path, got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package fixture
+
+import (
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Pages are rendered HTML snapshots used by the Chrome collector. They live
+// next to the HTTP fixtures as <name>.html.
+
+func SavePage(dir, pageURL, html string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, Name(http.MethodGet, pageURL)+".html"), []byte(html), 0644)
+}
+
+// PageRecorder returns a hook for chrome.WithPageRecorder that stores every
+// rendered page under dir.
+func PageRecorder(dir string) func(pageURL, html string) error {
+	return func(pageURL, html string) error {
+		return SavePage(dir, pageURL, html)
+	}
+}
+
+// StaticServer serves recorded pages from a local HTTP server so the Chrome
+// collector can run against them without network access. It is used by the
+// server's replay mode as well as tests, so it avoids net/http/httptest.
+type StaticServer struct {
+	URL    string
+	server *http.Server
+	dir    string
+
+	mu      sync.Mutex
+	origins map[string]string
+}
+
+func NewStaticServer(dir string) *StaticServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("fixture: listen for static pages: %v", err))
+	}
+
+	s := &StaticServer{dir: dir, origins: make(map[string]string)}
+	s.URL = "http://" + ln.Addr().String()
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serve)}
+	go s.server.Serve(ln)
+	return s
+}
+
+func (s *StaticServer) Close() error {
+	return s.server.Close()
+}
+
+// Rewrite maps a live page URL to its snapshot on the static server. Use it
+// as chrome.WithURLRewriter.
+func (s *StaticServer) Rewrite(pageURL string) string {
+	name := Name(http.MethodGet, pageURL) + ".html"
+
+	s.mu.Lock()
+	s.origins[name] = pageURL
+	s.mu.Unlock()
+
+	return s.URL + "/" + name
+}
+
+func (s *StaticServer) serve(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Base(strings.TrimPrefix(r.URL.Path, "/"))
+	if !strings.HasSuffix(name, ".html") {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Resolve relative links against the live page rather than the local
+	// server, so collected URLs match what a live run would produce.
+	s.mu.Lock()
+	origin := s.origins[name]
+	s.mu.Unlock()
+	page := string(data)
+	if origin != "" {
+		page = injectBase(page, origin)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(page))
+}
+
+var headTag = regexp.MustCompile(`(?i)<head(?:\s[^>]*)?>`)
+
+func injectBase(page, origin string) string {
+	base := `<base href="` + html.EscapeString(origin) + `">`
+	if loc := headTag.FindStringIndex(page); loc != nil {
+		return page[:loc[1]] + base + page[loc[1]:]
+	}
+	return base + page
+}
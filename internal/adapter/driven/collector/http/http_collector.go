@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,12 +19,26 @@ type HTTPCollector struct {
 	client *http.Client
 }
 
-func New() *HTTPCollector {
-	return &HTTPCollector{
+type Option func(*HTTPCollector)
+
+// WithTransport replaces the HTTP transport, e.g. with a fixture recorder
+// or replayer.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *HTTPCollector) {
+		c.client.Transport = rt
+	}
+}
+
+func New(opts ...Option) *HTTPCollector {
+	c := &HTTPCollector{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *HTTPCollector) Type() valueobject.CollectorType {
@@ -74,6 +89,7 @@ func (c *HTTPCollector) Collect(ctx context.Context, source *entity.Source) ([]*
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch story IDs: %w", err)
 	}
+	slog.DebugContext(ctx, "fetched story IDs", "url", url, "count", len(ids), "limit", limit)
 
 	if len(ids) > limit {
 		ids = ids[:limit]
@@ -87,6 +103,7 @@ func (c *HTTPCollector) Collect(ctx context.Context, source *entity.Source) ([]*
 
 		item, err := c.fetchItem(ctx, itemURLFilled)
 		if err != nil {
+			slog.WarnContext(ctx, "item fetch failed, skipping", "url", itemURLFilled, "error", err)
 			continue
 		}
 
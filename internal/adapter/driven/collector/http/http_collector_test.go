@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/collector/fixture"
+	"r3f-trends/internal/adapter/driven/collector/fixture/fixturetest"
+	httpcollector "r3f-trends/internal/adapter/driven/collector/http"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/domain/entity"
+)
+
+var (
+	update = flag.Bool("update", false, "rewrite golden files")
+	record = flag.Bool("record", false, "re-record fixtures from the live sources")
+)
+
+const (
+	sourcesDir  = "../../../../../config/sources"
+	fixturesDir = "testdata/fixtures"
+	goldenDir   = "testdata/golden"
+)
+
+func TestCollectGolden(t *testing.T) {
+	sources, err := yaml.NewSourceLoader(sourcesDir).LoadAll(context.Background())
+	if err != nil {
+		t.Fatalf("load sources: %v", err)
+	}
+
+	collector := httpcollector.New(httpcollector.WithTransport(fixture.NewReplayer(fixturesDir)))
+	if *record {
+		collector = httpcollector.New(httpcollector.WithTransport(fixture.NewRecorder(fixturesDir, nil)))
+	}
+
+	tested := 0
+	for _, source := range sources {
+		if source.Type() != "http" {
+			continue
+		}
+		tested++
+
+		t.Run(source.ID(), func(t *testing.T) {
+			trends, err := collector.Collect(context.Background(), source)
+			if err != nil {
+				t.Fatalf("Collect: %v", err)
+			}
+			if len(trends) == 0 {
+				t.Fatal("no trends collected")
+			}
+
+			fixturetest.AssertGolden(t, filepath.Join(goldenDir, source.ID()+".json"), trends, *update || *record)
+		})
+	}
+
+	if tested == 0 {
+		t.Fatal("no http sources found")
+	}
+}
+
+func TestCollectMissingFixture(t *testing.T) {
+	source := entity.NewSource("missing", "Missing", "http")
+	source.SetConfig(map[string]any{
+		"url":      "https://example.com/nothing.json",
+		"item_url": "https://example.com/item/{id}.json",
+	})
+
+	collector := httpcollector.New(httpcollector.WithTransport(fixture.NewReplayer(fixturesDir)))
+	if _, err := collector.Collect(context.Background(), source); err == nil {
+		t.Fatal("expected error for unrecorded request")
+	}
+}
+
+func TestCollectSkipsMissingItems(t *testing.T) {
+	dir := t.TempDir()
+	for _, rec := range []fixture.Response{
+		{Method: "GET", URL: "https://example.com/ids.json", Status: 200, JSON: []byte("[1,2]")},
+		{Method: "GET", URL: "https://example.com/item/1.json", Status: 200,
+			JSON: []byte(`{"id":1,"title":"Recorded story","url":"https://example.com/1","score":10,"time":1792396800,"type":"story"}`)},
+	} {
+		if err := fixture.Save(dir, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := entity.NewSource("partial", "Partial", "http")
+	source.SetConfig(map[string]any{
+		"url":      "https://example.com/ids.json",
+		"item_url": "https://example.com/item/{id}.json",
+	})
+
+	collector := httpcollector.New(httpcollector.WithTransport(fixture.NewReplayer(dir)))
+	trends, err := collector.Collect(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(trends) != 1 || trends[0].Title() != "Recorded story" {
+		t.Errorf("trends = %+v, want only the recorded item", trends)
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"r3f-trends/internal/app/prompt"
+	"r3f-trends/internal/domain"
 	"r3f-trends/internal/domain/entity"
 )
 
@@ -19,6 +24,14 @@ type Config struct {
 	LLM           LLMConfig       `yaml:"llm"`
 	Storage       StorageConfig   `yaml:"storage"`
 	Logging       LoggingConfig   `yaml:"logging"`
+	Fixtures      FixturesConfig  `yaml:"fixtures"`
+	Content       ContentConfig   `yaml:"content"`
+	Enrichment    EnrichConfig    `yaml:"enrichment"`
+	Embedding     EmbeddingConfig `yaml:"embedding"`
+	Clustering    ClusterConfig   `yaml:"clustering"`
+	Keywords      KeywordConfig   `yaml:"keywords"`
+	Webhooks      WebhookConfig   `yaml:"webhooks"`
+	Digest        DigestConfig    `yaml:"digest"`
 }
 
 type ServerConfig struct {
@@ -46,10 +59,49 @@ type ChromeConfig struct {
 }
 
 type LLMConfig struct {
-	Provider string `yaml:"provider"`
-	Model    string `yaml:"model"`
-	APIKey   string `yaml:"api_key"`
-	BaseURL  string `yaml:"base_url"`
+	Provider    string   `yaml:"provider"`
+	Model       string   `yaml:"model"`
+	APIKey      string   `yaml:"api_key"`
+	BaseURL     string   `yaml:"base_url"`
+	Temperature *float64 `yaml:"temperature"`
+	MaxTokens   int      `yaml:"max_tokens"`
+	Timeout     string   `yaml:"timeout"`
+	Fixtures    string   `yaml:"fixtures"`
+	// ResponseFormat: json_schema | json_object | none (OpenAI-compatible
+	// providers only).
+	ResponseFormat string `yaml:"response_format"`
+	// SummaryHistory is how many summaries to keep per trend.
+	SummaryHistory int `yaml:"summary_history"`
+	// SuggestionHistory is how many reviewed suggestions the suggester is
+	// shown to avoid repeats.
+	SuggestionHistory int            `yaml:"suggestion_history"`
+	Cache             LLMCacheConfig `yaml:"cache"`
+	Usage             LLMUsageConfig `yaml:"usage"`
+	Retry             LLMRetryConfig `yaml:"retry"`
+}
+
+type LLMRetryConfig struct {
+	MaxAttempts int    `yaml:"max_attempts"`
+	BaseDelay   string `yaml:"base_delay"`
+	MaxDelay    string `yaml:"max_delay"`
+}
+
+type LLMUsageConfig struct {
+	// Prices per million tokens, keyed by "provider/model" or "model".
+	Prices      map[string]PriceConfig `yaml:"prices"`
+	DailyBudget float64                `yaml:"daily_budget"`
+	BudgetMode  string                 `yaml:"budget_mode"`
+}
+
+type PriceConfig struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+type LLMCacheConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	TTL      string `yaml:"ttl"`
+	MaxBytes int64  `yaml:"max_bytes"`
 }
 
 type StorageConfig struct {
@@ -62,6 +114,102 @@ type LoggingConfig struct {
 	Format string `yaml:"format"`
 }
 
+type ContentConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	MaxBytes   int64  `yaml:"max_bytes"`
+	MaxChars   int    `yaml:"max_chars"`
+	ChunkChars int    `yaml:"chunk_chars"`
+	CacheTTL   string `yaml:"cache_ttl"`
+}
+
+type EnrichConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	Summarize   bool `yaml:"summarize"`
+	Tag         bool `yaml:"tag"`
+	Concurrency int  `yaml:"concurrency"`
+	MaxPerRun   int  `yaml:"max_per_run"`
+}
+
+type EmbeddingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Provider: hashed | openai
+	Provider   string `yaml:"provider"`
+	Model      string `yaml:"model"`
+	APIKey     string `yaml:"api_key"`
+	BaseURL    string `yaml:"base_url"`
+	Dimensions int    `yaml:"dimensions"`
+	Timeout    string `yaml:"timeout"`
+}
+
+type ClusterConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Threshold is the similarity needed to join a cluster; 0 picks a
+	// default for embeddings or title overlap.
+	Threshold    float64 `yaml:"threshold"`
+	LookbackDays int     `yaml:"lookback_days"`
+	NameWithLLM  bool    `yaml:"name_with_llm"`
+}
+
+type KeywordConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// PerTrend is how many keywords are added to each trend's tags.
+	PerTrend int `yaml:"per_trend"`
+}
+
+type WebhookConfig struct {
+	// MaxAttempts and Backoff control retries; the wait doubles after each
+	// failed attempt.
+	MaxAttempts int           `yaml:"max_attempts"`
+	Backoff     string        `yaml:"backoff"`
+	Timeout     string        `yaml:"timeout"`
+	Sinks       []WebhookSink `yaml:"sinks"`
+}
+
+type WebhookSink struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Format: json | slack | discord | ntfy
+	Format string   `yaml:"format"`
+	Secret string   `yaml:"secret"`
+	Events []string `yaml:"events"`
+}
+
+type DigestConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Schedule is the time of day (HH:MM, scheduler time zone) at which the
+	// digest of the last Days complete days is generated; empty means only
+	// on request.
+	Schedule string `yaml:"schedule"`
+	Days     int    `yaml:"days"`
+	Limit    int    `yaml:"limit"`
+	// Send emails scheduled digests.
+	Send    bool   `yaml:"send"`
+	Subject string `yaml:"subject"`
+	// Templates are paths to Go templates, relative to the config
+	// directory; empty ones use the built-in templates.
+	Templates DigestTemplates `yaml:"templates"`
+	Email     EmailConfig     `yaml:"email"`
+}
+
+type DigestTemplates struct {
+	Markdown string `yaml:"markdown"`
+	HTML     string `yaml:"html"`
+}
+
+type EmailConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+type FixturesConfig struct {
+	Mode string `yaml:"mode"`
+	Dir  string `yaml:"dir"`
+}
+
 type ConfigLoader struct {
 	configPath string
 }
@@ -88,6 +236,15 @@ func (l *ConfigLoader) Load() (*Config, error) {
 
 func (l *ConfigLoader) expandEnv(cfg *Config) {
 	cfg.LLM.APIKey = os.ExpandEnv(cfg.LLM.APIKey)
+	cfg.LLM.BaseURL = os.ExpandEnv(cfg.LLM.BaseURL)
+	cfg.Embedding.APIKey = os.ExpandEnv(cfg.Embedding.APIKey)
+	cfg.Embedding.BaseURL = os.ExpandEnv(cfg.Embedding.BaseURL)
+	cfg.Digest.Email.Username = os.ExpandEnv(cfg.Digest.Email.Username)
+	cfg.Digest.Email.Password = os.ExpandEnv(cfg.Digest.Email.Password)
+	for i := range cfg.Webhooks.Sinks {
+		cfg.Webhooks.Sinks[i].URL = os.ExpandEnv(cfg.Webhooks.Sinks[i].URL)
+		cfg.Webhooks.Sinks[i].Secret = os.ExpandEnv(cfg.Webhooks.Sinks[i].Secret)
+	}
 }
 
 type SourceLoader struct {
@@ -134,6 +291,40 @@ func (l *SourceLoader) LoadByProfile(ctx context.Context, profile string) ([]*en
 	return sources, nil
 }
 
+// ImportedSourcesFile is where AppendSources writes, next to the
+// hand-written source files of a profile.
+const ImportedSourcesFile = "imported.yaml"
+
+// AppendSources adds sources to the profile's imported.yaml, creating it if
+// needed. Hand-written source files are left alone.
+func (l *SourceLoader) AppendSources(ctx context.Context, profile string, sources []*entity.Source) error {
+	dir := filepath.Join(l.sourcesPath, profile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	filename := filepath.Join(dir, ImportedSourcesFile)
+
+	var fileStruct struct {
+		Sources []entity.SourceDTO `yaml:"sources"`
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &fileStruct); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	for _, s := range sources {
+		fileStruct.Sources = append(fileStruct.Sources, *s.ToDTO())
+	}
+	data, err = yaml.Marshal(fileStruct)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
 type ProfileLoader struct {
 	profilesPath string
 }
@@ -143,8 +334,14 @@ func NewProfileLoader(profilesPath string) *ProfileLoader {
 }
 
 func (l *ProfileLoader) Load(ctx context.Context, name string) (*entity.Profile, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("%w: %s", domain.ErrProfileNotFound, name)
+	}
 	data, err := os.ReadFile(filepath.Join(l.profilesPath, name+".yaml"))
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProfileNotFound, name)
+		}
 		return nil, err
 	}
 
@@ -153,6 +350,10 @@ func (l *ProfileLoader) Load(ctx context.Context, name string) (*entity.Profile,
 		return nil, err
 	}
 
+	if err := validateProfile(&dto); err != nil {
+		return nil, err
+	}
+
 	return entity.ProfileFromDTO(&dto), nil
 }
 
@@ -175,8 +376,59 @@ func (l *ProfileLoader) LoadAll(ctx context.Context) ([]*entity.Profile, error)
 			continue
 		}
 
+		if err := validateProfile(&dto); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+
 		profiles = append(profiles, entity.ProfileFromDTO(&dto))
 	}
 
 	return profiles, nil
 }
+
+func validateProfile(dto *entity.ProfileDTO) error {
+	if err := prompt.Validate(dto.Prompts.Summarizer); err != nil {
+		return fmt.Errorf("%w: profile %s: prompts.summarizer: %v", domain.ErrInvalidConfig, dto.Name, err)
+	}
+	if err := prompt.Validate(dto.Prompts.Suggester); err != nil {
+		return fmt.Errorf("%w: profile %s: prompts.suggester: %v", domain.ErrInvalidConfig, dto.Name, err)
+	}
+	if err := prompt.Validate(dto.Prompts.Tagger); err != nil {
+		return fmt.Errorf("%w: profile %s: prompts.tagger: %v", domain.ErrInvalidConfig, dto.Name, err)
+	}
+	if err := prompt.Validate(dto.Prompts.Outliner); err != nil {
+		return fmt.Errorf("%w: profile %s: prompts.outliner: %v", domain.ErrInvalidConfig, dto.Name, err)
+	}
+	if err := prompt.Validate(dto.Prompts.Drafter); err != nil {
+		return fmt.Errorf("%w: profile %s: prompts.drafter: %v", domain.ErrInvalidConfig, dto.Name, err)
+	}
+	if err := prompt.Validate(dto.Prompts.Namer); err != nil {
+		return fmt.Errorf("%w: profile %s: prompts.namer: %v", domain.ErrInvalidConfig, dto.Name, err)
+	}
+	if w := dto.Selection.Window; w != "" {
+		if d, err := time.ParseDuration(w); err != nil || d <= 0 {
+			return fmt.Errorf("%w: profile %s: selection.window: %q is not a positive duration", domain.ErrInvalidConfig, dto.Name, w)
+		}
+	}
+	for source, quota := range dto.Selection.SourceQuotas {
+		if quota < 0 {
+			return fmt.Errorf("%w: profile %s: selection.source_quotas.%s: must not be negative", domain.ErrInvalidConfig, dto.Name, source)
+		}
+	}
+	names := make(map[string]bool)
+	for i, rule := range dto.Watch {
+		switch {
+		case rule.Name == "":
+			return fmt.Errorf("%w: profile %s: watch[%d]: name is required", domain.ErrInvalidConfig, dto.Name, i)
+		case names[rule.Name]:
+			return fmt.Errorf("%w: profile %s: watch.%s: duplicate name", domain.ErrInvalidConfig, dto.Name, rule.Name)
+		case len(rule.Keywords) == 0 && rule.Regex == "":
+			return fmt.Errorf("%w: profile %s: watch.%s: needs keywords or a regex", domain.ErrInvalidConfig, dto.Name, rule.Name)
+		}
+		if _, err := regexp.Compile(rule.Regex); err != nil {
+			return fmt.Errorf("%w: profile %s: watch.%s.regex: %v", domain.ErrInvalidConfig, dto.Name, rule.Name, err)
+		}
+		names[rule.Name] = true
+	}
+	return nil
+}
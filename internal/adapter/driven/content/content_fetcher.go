@@ -0,0 +1,213 @@
+package content
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrUnsupportedContent = errors.New("unsupported content type")
+
+const (
+	DefaultMaxBytes = 2 << 20
+	DefaultMaxChars = 20000
+	DefaultCacheTTL = 7 * 24 * time.Hour
+)
+
+// Fetcher downloads a page and returns its readable text. Results are cached
+// in memory and, when cacheDir is set, on disk so a URL is only downloaded
+// once per TTL.
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+	maxChars int
+	cacheDir string
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+type cached struct {
+	text      string
+	fetchedAt time.Time
+}
+
+type Option func(*Fetcher)
+
+func WithMaxBytes(n int64) Option {
+	return func(f *Fetcher) {
+		if n > 0 {
+			f.maxBytes = n
+		}
+	}
+}
+
+func WithMaxChars(n int) Option {
+	return func(f *Fetcher) {
+		if n > 0 {
+			f.maxChars = n
+		}
+	}
+}
+
+func WithCache(dir string, ttl time.Duration) Option {
+	return func(f *Fetcher) {
+		f.cacheDir = dir
+		if ttl > 0 {
+			f.cacheTTL = ttl
+		}
+	}
+}
+
+func WithTransport(rt http.RoundTripper) Option {
+	return func(f *Fetcher) {
+		f.client.Transport = rt
+	}
+}
+
+func New(opts ...Option) *Fetcher {
+	f := &Fetcher{
+		client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		maxBytes: DefaultMaxBytes,
+		maxChars: DefaultMaxChars,
+		cacheTTL: DefaultCacheTTL,
+		cache:    make(map[string]cached),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, url string) (string, error) {
+	if text, ok := f.lookup(url); ok {
+		return text, nil
+	}
+
+	text, err := f.download(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	f.store(url, text)
+	return text, nil
+}
+
+func (f *Fetcher) download(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "r3f-signal-agent/1.0 (+https://github.com/tillknuesting/r3f-signal-agent)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "", mediaType == "text/html", mediaType == "application/xhtml+xml", mediaType == "text/plain", mediaType == "text/markdown":
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedContent, mediaType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes))
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(body) {
+		body = []byte(strings.ToValidUTF8(string(body), ""))
+	}
+
+	var text string
+	if mediaType == "text/plain" || mediaType == "text/markdown" {
+		text = ExtractText(string(body))
+	} else {
+		_, text = ExtractHTML(string(body))
+	}
+
+	return truncate(text, f.maxChars), nil
+}
+
+func (f *Fetcher) lookup(url string) (string, bool) {
+	f.mu.Lock()
+	c, ok := f.cache[url]
+	f.mu.Unlock()
+	if ok && time.Since(c.fetchedAt) < f.cacheTTL {
+		return c.text, true
+	}
+
+	if f.cacheDir == "" {
+		return "", false
+	}
+
+	path := f.cachePath(url)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) >= f.cacheTTL {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	f.mu.Lock()
+	f.cache[url] = cached{text: string(data), fetchedAt: info.ModTime()}
+	f.mu.Unlock()
+	return string(data), true
+}
+
+func (f *Fetcher) store(url, text string) {
+	f.mu.Lock()
+	f.cache[url] = cached{text: text, fetchedAt: time.Now()}
+	f.mu.Unlock()
+
+	if f.cacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(f.cacheDir, 0755); err != nil {
+		return
+	}
+	os.WriteFile(f.cachePath(url), []byte(text), 0644)
+}
+
+func (f *Fetcher) cachePath(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(f.cacheDir, hex.EncodeToString(sum[:])+".txt")
+}
+
+// truncate cuts text to at most max bytes at a paragraph or word boundary.
+func truncate(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+	cut := text[:max]
+	if i := strings.LastIndex(cut, "\n\n"); i > max/2 {
+		return cut[:i]
+	}
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.ToValidUTF8(cut, "")
+}
@@ -0,0 +1,123 @@
+package content_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/content"
+)
+
+const article = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Go 1.26 is released - The Go Programming Language</title>
+  <style>body { font-family: sans-serif; }</style>
+  <script>window.analytics = {};</script>
+</head>
+<body>
+  <header><a href="/">Home</a> <a href="/blog">Blog</a></header>
+  <nav><ul><li><a href="/doc">Documentation</a></li><li><a href="/pkg">Packages</a></li></ul></nav>
+  <article>
+    <h1>Go 1.26 is released</h1>
+    <p>Today the Go team is very happy to announce the release of Go 1.26. You can get it from the download page.</p>
+    <p>Go 1.26 brings a new garbage collector by default, reducing GC overhead by up to 40% in real-world programs.</p>
+    <div class="share"><a href="https://x.com/share">Share</a> <a href="https://bsky.app/share">Post</a></div>
+    <p><a href="/doc/go1.26">Read the full release notes for all of the details and breaking changes in this release.</a></p>
+    <p>Thanks to everyone who contributed to this release by writing code &amp; filing bugs.</p>
+  </article>
+  <footer>Copyright 2026 The Go Authors</footer>
+</body>
+</html>`
+
+func TestExtractHTML(t *testing.T) {
+	title, text := content.ExtractHTML(article)
+
+	if title != "Go 1.26 is released - The Go Programming Language" {
+		t.Errorf("title = %q", title)
+	}
+
+	want := strings.Join([]string{
+		"Go 1.26 is released",
+		"Today the Go team is very happy to announce the release of Go 1.26. You can get it from the download page.",
+		"Go 1.26 brings a new garbage collector by default, reducing GC overhead by up to 40% in real-world programs.",
+		"Thanks to everyone who contributed to this release by writing code & filing bugs.",
+	}, "\n\n")
+	if text != want {
+		t.Errorf("text =\n%s\n\nwant\n%s", text, want)
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	got := content.ExtractText("First   paragraph\nstill first.\r\n\r\n\n\nSecond.")
+	if got != "First paragraph still first.\n\nSecond." {
+		t.Errorf("ExtractText = %q", got)
+	}
+}
+
+func TestFetcher(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/article":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, article)
+		case "/notes.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, strings.Repeat("word ", 100))
+		case "/paper.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			io.WriteString(w, "%PDF-1.7")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	fetcher := content.New(content.WithMaxChars(100), content.WithCache(cacheDir, time.Hour))
+	ctx := context.Background()
+
+	text, err := fetcher.Fetch(ctx, srv.URL+"/article")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !strings.HasPrefix(text, "Go 1.26 is released") || len(text) > 100 {
+		t.Errorf("text = %q", text)
+	}
+
+	if _, err := fetcher.Fetch(ctx, srv.URL+"/article"); err != nil {
+		t.Fatalf("cached Fetch: %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("server hit %d times, want 1 (cached)", hits.Load())
+	}
+
+	// A fresh fetcher sharing the cache directory does not download again.
+	if _, err := content.New(content.WithCache(cacheDir, time.Hour)).Fetch(ctx, srv.URL+"/article"); err != nil {
+		t.Fatalf("disk cached Fetch: %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("server hit %d times, want 1 (disk cache)", hits.Load())
+	}
+
+	text, err = fetcher.Fetch(ctx, srv.URL+"/notes.txt")
+	if err != nil || len(text) > 100 {
+		t.Errorf("plain text = %q, %v", text, err)
+	}
+
+	if _, err := fetcher.Fetch(ctx, srv.URL+"/paper.pdf"); !errors.Is(err, content.ErrUnsupportedContent) {
+		t.Errorf("pdf error = %v, want ErrUnsupportedContent", err)
+	}
+
+	if _, err := fetcher.Fetch(ctx, srv.URL+"/missing"); err == nil {
+		t.Error("expected error for 404")
+	}
+}
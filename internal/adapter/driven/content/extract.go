@@ -0,0 +1,118 @@
+package content
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	commentRe  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	titleRe    = regexp.MustCompile(`(?is)<title\b[^>]*>(.*?)</title\s*>`)
+	articleRe  = regexp.MustCompile(`(?is)<article\b[^>]*>(.*)</article\s*>`)
+	mainRe     = regexp.MustCompile(`(?is)<main\b[^>]*>(.*)</main\s*>`)
+	bodyRe     = regexp.MustCompile(`(?is)<body\b[^>]*>(.*)</body\s*>`)
+	blockRe    = regexp.MustCompile(`(?i)</?(p|div|section|br|li|ul|ol|h[1-6]|pre|blockquote|tr|table|dd|dt|figcaption)\b[^>]*>`)
+	headingRe  = regexp.MustCompile(`(?i)^<h[1-6]\b`)
+	anchorRe   = regexp.MustCompile(`(?is)<a\b[^>]*>(.*?)</a\s*>`)
+	tagRe      = regexp.MustCompile(`(?s)<[^>]*>`)
+	spaceRe    = regexp.MustCompile(`\s+`)
+	minParaLen = 40
+
+	boilerplate = boilerplateRes("script", "style", "noscript", "template", "svg", "iframe",
+		"form", "button", "select", "nav", "aside", "header", "footer")
+)
+
+func boilerplateRes(tags ...string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(tags))
+	for i, tag := range tags {
+		res[i] = regexp.MustCompile(`(?is)<` + tag + `\b[^>]*>.*?</` + tag + `\s*>`)
+	}
+	return res
+}
+
+// ExtractHTML returns the readable main text of an HTML document: markup,
+// scripts and navigation chrome are dropped, the <article> or <main> region
+// is preferred when present, and short or link-heavy blocks are discarded.
+// Paragraphs are separated by blank lines.
+func ExtractHTML(doc string) (title, text string) {
+	if m := titleRe.FindStringSubmatch(doc); m != nil {
+		title = clean(m[1])
+	}
+
+	doc = commentRe.ReplaceAllString(doc, "")
+	for _, re := range boilerplate {
+		doc = re.ReplaceAllString(doc, "")
+	}
+
+	region := doc
+	for _, re := range []*regexp.Regexp{articleRe, mainRe, bodyRe} {
+		if m := re.FindStringSubmatch(doc); m != nil && strings.TrimSpace(tagRe.ReplaceAllString(m[1], "")) != "" {
+			region = m[1]
+			break
+		}
+	}
+
+	var paragraphs []string
+	idx := blockRe.FindAllStringIndex(region, -1)
+	prev := 0
+	flush := func(block string, heading bool) {
+		p := clean(tagRe.ReplaceAllString(block, " "))
+		if p == "" {
+			return
+		}
+		if !heading && (len(p) < minParaLen || linkDensity(block, p) > 0.5) {
+			return
+		}
+		paragraphs = append(paragraphs, p)
+	}
+
+	heading := false
+	for _, loc := range idx {
+		flush(region[prev:loc[0]], heading)
+		heading = headingRe.MatchString(region[loc[0]:loc[1]])
+		prev = loc[1]
+	}
+	flush(region[prev:], heading)
+
+	return title, strings.Join(dedupe(paragraphs), "\n\n")
+}
+
+// ExtractText normalises a plain-text document into paragraphs.
+func ExtractText(doc string) string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(spaceRe.ReplaceAllString(p, " ")); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func clean(s string) string {
+	return strings.TrimSpace(spaceRe.ReplaceAllString(html.UnescapeString(s), " "))
+}
+
+func linkDensity(block, text string) float64 {
+	if text == "" {
+		return 0
+	}
+	linkText := 0
+	for _, m := range anchorRe.FindAllStringSubmatch(block, -1) {
+		linkText += len(clean(tagRe.ReplaceAllString(m[1], " ")))
+	}
+	return float64(linkText) / float64(len(text))
+}
+
+func dedupe(paragraphs []string) []string {
+	seen := make(map[string]bool, len(paragraphs))
+	out := paragraphs[:0]
+	for _, p := range paragraphs {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out
+}
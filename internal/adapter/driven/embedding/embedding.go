@@ -0,0 +1,31 @@
+package embedding
+
+import (
+	"fmt"
+	"time"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+)
+
+type Config struct {
+	// Provider is "hashed" (the default, no network) or "openai" for any
+	// OpenAI-compatible /embeddings endpoint.
+	Provider   string
+	Model      string
+	APIKey     string
+	BaseURL    string
+	Dimensions int
+	Timeout    time.Duration
+}
+
+func New(cfg Config) (service.Embedder, error) {
+	switch cfg.Provider {
+	case "", "hashed":
+		return NewHashed(cfg.Dimensions), nil
+	case "openai":
+		return NewOpenAI(cfg)
+	default:
+		return nil, fmt.Errorf("%w: unknown embedding provider %q", domain.ErrInvalidConfig, cfg.Provider)
+	}
+}
@@ -0,0 +1,86 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"r3f-trends/internal/app/service"
+)
+
+const DefaultHashedDimensions = 512
+
+// Hashed embeds text as a hashed bag of words and word pairs. It needs no
+// model or network access and is enough to tell that differently worded
+// titles share their names and terms.
+type Hashed struct {
+	dims int
+}
+
+func NewHashed(dims int) *Hashed {
+	if dims <= 0 {
+		dims = DefaultHashedDimensions
+	}
+	return &Hashed{dims: dims}
+}
+
+func (h *Hashed) Name() string {
+	return fmt.Sprintf("hashed-%d", h.dims)
+}
+
+func (h *Hashed) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = h.embed(text)
+	}
+	return vectors, nil
+}
+
+func (h *Hashed) embed(text string) []float32 {
+	vec := make([]float32, h.dims)
+
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	}) {
+		w = strings.Trim(w, ".")
+		if w != "" && !service.IsStopWord(w) {
+			words = append(words, w)
+		}
+	}
+
+	for i, w := range words {
+		h.add(vec, w, 1)
+		if i > 0 {
+			h.add(vec, words[i-1]+" "+w, 0.5)
+		}
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vec {
+			vec[i] *= scale
+		}
+	}
+	return vec
+}
+
+// add hashes feature into a bucket, using one more hash bit as the sign so
+// collisions tend to cancel out rather than add up.
+func (h *Hashed) add(vec []float32, feature string, weight float32) {
+	hash := fnv.New64a()
+	hash.Write([]byte(feature))
+	sum := hash.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vec[sum%uint64(h.dims)] += weight
+}
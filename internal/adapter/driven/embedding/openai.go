@@ -0,0 +1,121 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/agent/llm"
+)
+
+const (
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+	DefaultOpenAIModel   = "text-embedding-3-small"
+)
+
+// OpenAI calls an OpenAI-compatible /embeddings endpoint.
+type OpenAI struct {
+	apiKey  string
+	baseURL string
+	model   string
+	dims    int
+	client  *http.Client
+}
+
+type embeddingsRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func NewOpenAI(cfg Config) (*OpenAI, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("embedding: %w", llm.ErrMissingAPIKey)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultOpenAIBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultOpenAIModel
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	return &OpenAI{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		model:   cfg.Model,
+		dims:    cfg.Dimensions,
+		client:  &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (e *OpenAI) Name() string {
+	if e.dims > 0 {
+		return fmt.Sprintf("openai-%s-%d", e.model, e.dims)
+	}
+	return "openai-" + e.model
+}
+
+func (e *OpenAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: e.model, Input: texts, Dimensions: e.dims})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, llm.NewTransportError("embedding", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, llm.NewTransportError("embedding", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, llm.NewHTTPError("embedding", resp, respBody)
+	}
+
+	var out embeddingsResponse
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, llm.NewDecodeError("embedding", resp, respBody, err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding: response index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("embedding: no vector returned for input %d", i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,51 @@
+package embedding_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/embedding"
+	"r3f-trends/internal/domain"
+)
+
+func TestOpenAIEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer key" {
+			http.Error(w, `{"error":{"message":"bad request"}}`, http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		// Answer out of order to check that indexes are honoured.
+		json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+			{"index": 1, "embedding": []float32{0, 1}},
+			{"index": 0, "embedding": []float32{1, 0}},
+		}})
+	}))
+	defer srv.Close()
+
+	e, err := embedding.New(embedding.Config{Provider: "openai", APIKey: "key", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	vectors, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("vectors = %v", vectors)
+	}
+
+	bad, _ := embedding.New(embedding.Config{Provider: "openai", APIKey: "wrong", BaseURL: srv.URL})
+	if _, err := bad.Embed(context.Background(), []string{"a"}); !errors.Is(err, domain.ErrLLMAuth) {
+		t.Errorf("err = %v, want ErrLLMAuth", err)
+	}
+}
@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP accepts one message and sends what it received on the channel.
+func fakeSMTP(t *testing.T) (string, int, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+
+		var transcript strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			transcript.WriteString(line)
+			switch cmd := strings.ToUpper(strings.Fields(line + " x")[0]); cmd {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					transcript.WriteString(line)
+				}
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return "127.0.0.1", addr.Port, received
+}
+
+func TestSendMultipart(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+
+	m := NewSMTP(Config{Host: host, Port: port, From: "trends@example.com"})
+	err := m.Send(context.Background(), []string{"team@example.com"}, "Tech digest für 2026-10-19",
+		"# Digest\n\nGo 1.26 is released", "<h1>Digest</h1>")
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got := <-received
+	for _, want := range []string{
+		"MAIL FROM:<trends@example.com>",
+		"RCPT TO:<team@example.com>",
+		"Subject: =?utf-8?q?Tech_digest_f=C3=BCr_2026-10-19?=",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/plain; charset=utf-8",
+		"Go 1.26 is released",
+		"Content-Type: text/html; charset=utf-8",
+		"<h1>Digest</h1>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message lacks %q:\n%s", want, got)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package markdown
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 
+	"r3f-trends/internal/app/metrics"
 	"r3f-trends/internal/app/service"
 	"r3f-trends/internal/domain/entity"
 )
@@ -17,11 +21,30 @@ func NewTrendRepositoryAdapter(basePath string) *TrendRepositoryAdapter {
 	}
 }
 
-func (a *TrendRepositoryAdapter) SaveBatch(ctx context.Context, trends []*entity.Trend) error {
+// observe records how long a storage operation took and logs it, with its
+// error if it failed.
+func observe(ctx context.Context, op string, started time.Time, err error) {
+	d := time.Since(started)
+	metrics.StorageDuration.Observe(d.Seconds(), op)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		slog.ErrorContext(ctx, "storage operation failed", "operation", op, "duration", d, "error", err)
+		return
+	}
+	slog.DebugContext(ctx, "storage operation", "operation", op, "duration", d)
+}
+
+func (a *TrendRepositoryAdapter) SaveBatch(ctx context.Context, trends []*entity.Trend) (err error) {
+	defer func(started time.Time) { observe(ctx, "save_batch", started, err) }(time.Now())
 	return a.repo.SaveBatch(ctx, trends)
 }
 
-func (a *TrendRepositoryAdapter) List(ctx context.Context, opts service.ListOptions) ([]*entity.Trend, int, error) {
+func (a *TrendRepositoryAdapter) MergeBatch(ctx context.Context, trends []*entity.Trend) (n int, err error) {
+	defer func(started time.Time) { observe(ctx, "merge_batch", started, err) }(time.Now())
+	return a.repo.MergeBatch(ctx, trends)
+}
+
+func (a *TrendRepositoryAdapter) List(ctx context.Context, opts service.ListOptions) (found []*entity.Trend, total int, err error) {
+	defer func(started time.Time) { observe(ctx, "list", started, err) }(time.Now())
 	return a.repo.List(ctx, ListOptions{
 		Limit:  opts.Limit,
 		Offset: opts.Offset,
@@ -30,11 +53,13 @@ func (a *TrendRepositoryAdapter) List(ctx context.Context, opts service.ListOpti
 	})
 }
 
-func (a *TrendRepositoryAdapter) FindByID(ctx context.Context, id string) (*entity.Trend, error) {
+func (a *TrendRepositoryAdapter) FindByID(ctx context.Context, id string) (found *entity.Trend, err error) {
+	defer func(started time.Time) { observe(ctx, "find_by_id", started, err) }(time.Now())
 	return a.repo.FindByID(ctx, id)
 }
 
-func (a *TrendRepositoryAdapter) FindByDate(ctx context.Context, date string, opts service.ListOptions) ([]*entity.Trend, int, error) {
+func (a *TrendRepositoryAdapter) FindByDate(ctx context.Context, date string, opts service.ListOptions) (found []*entity.Trend, total int, err error) {
+	defer func(started time.Time) { observe(ctx, "find_by_date", started, err) }(time.Now())
 	return a.repo.FindByDate(ctx, date, ListOptions{
 		Limit:  opts.Limit,
 		Offset: opts.Offset,
@@ -43,7 +68,8 @@ func (a *TrendRepositoryAdapter) FindByDate(ctx context.Context, date string, op
 	})
 }
 
-func (a *TrendRepositoryAdapter) Search(ctx context.Context, query string, opts service.SearchOptions) ([]*entity.Trend, int, error) {
+func (a *TrendRepositoryAdapter) Search(ctx context.Context, query string, opts service.SearchOptions) (found []*entity.Trend, total int, err error) {
+	defer func(started time.Time) { observe(ctx, "search", started, err) }(time.Now())
 	return a.repo.Search(ctx, query, SearchOptions{
 		Limit:    opts.Limit,
 		Offset:   opts.Offset,
@@ -55,10 +81,17 @@ func (a *TrendRepositoryAdapter) Search(ctx context.Context, query string, opts
 	})
 }
 
-func (a *TrendRepositoryAdapter) Update(ctx context.Context, trend *entity.Trend) error {
+func (a *TrendRepositoryAdapter) Update(ctx context.Context, trend *entity.Trend) (err error) {
+	defer func(started time.Time) { observe(ctx, "update", started, err) }(time.Now())
 	return a.repo.Update(ctx, trend)
 }
 
-func (a *TrendRepositoryAdapter) Delete(ctx context.Context, id string) error {
+func (a *TrendRepositoryAdapter) Delete(ctx context.Context, id string) (err error) {
+	defer func(started time.Time) { observe(ctx, "delete", started, err) }(time.Now())
 	return a.repo.Delete(ctx, id)
 }
+
+// CheckWritable reports whether trends can be written.
+func (a *TrendRepositoryAdapter) CheckWritable(ctx context.Context) error {
+	return a.repo.CheckWritable(ctx)
+}
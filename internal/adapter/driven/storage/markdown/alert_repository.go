@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// AlertRepository keeps every alert of a profile in
+// <base>/<profile>/alerts.md, oldest first.
+type AlertRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewAlertRepository(basePath string) *AlertRepository {
+	return &AlertRepository{basePath: basePath}
+}
+
+// SaveAlerts adds new alerts and replaces existing ones with the same ID.
+func (r *AlertRepository) SaveAlerts(ctx context.Context, profile string, alerts []*entity.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.load(profile)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(existing))
+	for i, a := range existing {
+		index[a.ID] = i
+	}
+	for _, a := range alerts {
+		if i, ok := index[a.ID]; ok {
+			existing[i] = a
+			continue
+		}
+		index[a.ID] = len(existing)
+		existing = append(existing, a)
+	}
+
+	return writeDocument(r.path(profile), []string{
+		"profile: " + profile,
+		fmt.Sprintf("count: %d", len(existing)),
+	}, existing)
+}
+
+func (r *AlertRepository) ListAlerts(ctx context.Context, profile string) ([]*entity.Alert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(profile)
+}
+
+func (r *AlertRepository) path(profile string) string {
+	return filepath.Join(r.basePath, profile, "alerts.md")
+}
+
+func (r *AlertRepository) load(profile string) ([]*entity.Alert, error) {
+	var alerts []*entity.Alert
+	if err := readDocument(r.path(profile), &alerts); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return alerts, nil
+}
@@ -0,0 +1,50 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// ClusterRepository keeps the storyline clusters of each day in
+// <base>/clusters/YYYY-MM-DD.md.
+type ClusterRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewClusterRepository(basePath string) *ClusterRepository {
+	return &ClusterRepository{basePath: basePath}
+}
+
+func (r *ClusterRepository) SaveClusters(ctx context.Context, date string, clusters []*entity.Cluster) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return writeDocument(r.path(date), []string{
+		"date: " + date,
+		fmt.Sprintf("count: %d", len(clusters)),
+	}, clusters)
+}
+
+func (r *ClusterRepository) ListClusters(ctx context.Context, date string) ([]*entity.Cluster, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clusters := []*entity.Cluster{}
+	if err := readDocument(r.path(date), &clusters); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return clusters, nil
+}
+
+func (r *ClusterRepository) path(date string) string {
+	return filepath.Join(r.basePath, "clusters", date+".md")
+}
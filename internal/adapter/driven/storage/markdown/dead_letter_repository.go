@@ -0,0 +1,64 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// maxDeadLetters caps the log; the oldest letters are dropped first.
+const maxDeadLetters = 500
+
+// DeadLetterRepository keeps the notifications that webhook sinks did not
+// accept in <base>/webhooks/dead-letters.md, oldest first.
+type DeadLetterRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewDeadLetterRepository(basePath string) *DeadLetterRepository {
+	return &DeadLetterRepository{basePath: basePath}
+}
+
+func (r *DeadLetterRepository) SaveDeadLetter(ctx context.Context, letter *entity.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	letters, err := r.load()
+	if err != nil {
+		return err
+	}
+	letters = append(letters, letter)
+	if len(letters) > maxDeadLetters {
+		letters = letters[len(letters)-maxDeadLetters:]
+	}
+
+	return writeDocument(r.path(), []string{
+		fmt.Sprintf("count: %d", len(letters)),
+	}, letters)
+}
+
+func (r *DeadLetterRepository) ListDeadLetters(ctx context.Context) ([]*entity.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load()
+}
+
+func (r *DeadLetterRepository) path() string {
+	return filepath.Join(r.basePath, "webhooks", "dead-letters.md")
+}
+
+func (r *DeadLetterRepository) load() ([]*entity.DeadLetter, error) {
+	var letters []*entity.DeadLetter
+	if err := readDocument(r.path(), &letters); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return letters, nil
+}
@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// DigestRepository writes each digest to <base>/<profile>/digests/<id>.md,
+// with the period and trend IDs in the frontmatter, and the HTML rendering
+// next to it as <id>.html. A digest for the same period replaces the
+// earlier one.
+type DigestRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewDigestRepository(basePath string) *DigestRepository {
+	return &DigestRepository{basePath: basePath}
+}
+
+func (r *DigestRepository) SaveDigest(ctx context.Context, d *entity.Digest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dir := filepath.Join(r.basePath, d.Profile, "digests")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	fields := []struct {
+		key   string
+		value any
+	}{
+		{"id", d.ID},
+		{"subject", d.Subject},
+		{"profile", d.Profile},
+		{"from", d.From},
+		{"to", d.To},
+		{"created_at", d.CreatedAt.Format(time.RFC3339)},
+		{"trend_ids", d.TrendIDs},
+		{"sent_to", d.SentTo},
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, f := range fields {
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s: %s\n", f.key, value)
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(d.Markdown)
+	if !strings.HasSuffix(d.Markdown, "\n") {
+		b.WriteString("\n")
+	}
+
+	path := filepath.Join(dir, d.ID+".md")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	htmlPath := filepath.Join(dir, d.ID+".html")
+	if err := os.WriteFile(htmlPath, []byte(d.HTML), 0644); err != nil {
+		return err
+	}
+
+	d.Path, d.HTMLPath = path, htmlPath
+	return nil
+}
@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readDocument decodes the fenced JSON block of a markdown file written by
+// writeDocument into v.
+func readDocument(filename string, v any) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	content := string(data)
+	start := strings.Index(content, "```json")
+	if start == -1 {
+		return fmt.Errorf("%s: no json block found", filename)
+	}
+	body := content[start+len("```json"):]
+	end := strings.Index(body, "```")
+	if end == -1 {
+		return fmt.Errorf("%s: unterminated json block", filename)
+	}
+
+	return json.Unmarshal([]byte(strings.TrimSpace(body[:end])), v)
+}
+
+// writeDocument stores v as a markdown file with the given frontmatter lines
+// and a fenced JSON block, the same layout as the trend files.
+func writeDocument(filename string, frontmatter []string, v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, line := range frontmatter {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("---\n\n```json\n")
+	b.Write(jsonData)
+	b.WriteString("\n```\n")
+
+	return os.WriteFile(filename, []byte(b.String()), 0644)
+}
@@ -0,0 +1,87 @@
+package markdown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// DraftRepository writes each draft to <base>/<profile>/drafts/<id>.md as
+// plain markdown, with the outline and sources in the frontmatter so the
+// file can be opened in any editor or dropped into a static site.
+type DraftRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewDraftRepository(basePath string) *DraftRepository {
+	return &DraftRepository{basePath: basePath}
+}
+
+// SaveDraft stores draft and sets its Path. A draft whose ID is already
+// taken gets a numeric suffix rather than overwriting the earlier one.
+func (r *DraftRepository) SaveDraft(ctx context.Context, draft *entity.Draft) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dir := filepath.Join(r.basePath, draft.Profile, "drafts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	id := draft.ID
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(dir, id+".md")); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", draft.ID, n)
+	}
+
+	fields := []struct {
+		key   string
+		value any
+	}{
+		{"id", id},
+		{"title", draft.Title},
+		{"profile", draft.Profile},
+		{"suggestion_id", draft.SuggestionID},
+		{"provider", draft.Provider},
+		{"model", draft.Model},
+		{"created_at", draft.CreatedAt.Format(time.RFC3339)},
+		{"sources", draft.Sources},
+		{"outline", draft.Outline},
+	}
+
+	// JSON values are valid YAML flow scalars and collections, so the
+	// frontmatter stays readable by static site generators.
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, f := range fields {
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s: %s\n", f.key, value)
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(draft.Markdown)
+	if !strings.HasSuffix(draft.Markdown, "\n") {
+		b.WriteString("\n")
+	}
+
+	filename := filepath.Join(dir, id+".md")
+	if err := os.WriteFile(filename, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+
+	draft.ID = id
+	draft.Path = filename
+	return nil
+}
@@ -0,0 +1,85 @@
+package markdown
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// EmbeddingRepository keeps the trend vectors of each embedder in
+// <base>/embeddings/<embedder>.md. Vectors are stored as base64 of their
+// little-endian float32 values to keep the files compact.
+type EmbeddingRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewEmbeddingRepository(basePath string) *EmbeddingRepository {
+	return &EmbeddingRepository{basePath: basePath}
+}
+
+func (r *EmbeddingRepository) LoadEmbeddings(ctx context.Context, embedder string) (map[string][]float32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var encoded map[string]string
+	if err := readDocument(r.path(embedder), &encoded); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	vectors := make(map[string][]float32, len(encoded))
+	for id, s := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || len(raw)%4 != 0 {
+			return nil, fmt.Errorf("embedding for %s: invalid vector", id)
+		}
+		v := make([]float32, len(raw)/4)
+		for i := range v {
+			v[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+		}
+		vectors[id] = v
+	}
+	return vectors, nil
+}
+
+func (r *EmbeddingRepository) SaveEmbeddings(ctx context.Context, embedder string, vectors map[string][]float32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dims := 0
+	encoded := make(map[string]string, len(vectors))
+	for id, v := range vectors {
+		raw := make([]byte, len(v)*4)
+		for i, f := range v {
+			binary.LittleEndian.PutUint32(raw[i*4:], math.Float32bits(f))
+		}
+		encoded[id] = base64.StdEncoding.EncodeToString(raw)
+		dims = len(v)
+	}
+
+	return writeDocument(r.path(embedder), []string{
+		"embedder: " + embedder,
+		fmt.Sprintf("count: %d", len(vectors)),
+		fmt.Sprintf("dimensions: %d", dims),
+	}, encoded)
+}
+
+func (r *EmbeddingRepository) path(embedder string) string {
+	name := strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '.' {
+			return c
+		}
+		return '_'
+	}, embedder)
+	return filepath.Join(r.basePath, "embeddings", name+".md")
+}
@@ -0,0 +1,100 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+// SuggestionRepository keeps every suggestion of a profile in
+// <base>/<profile>/suggestions.md, oldest first.
+type SuggestionRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewSuggestionRepository(basePath string) *SuggestionRepository {
+	return &SuggestionRepository{basePath: basePath}
+}
+
+// SaveSuggestions adds new suggestions and replaces existing ones with the
+// same ID.
+func (r *SuggestionRepository) SaveSuggestions(ctx context.Context, profile string, suggestions []*entity.Suggestion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.load(profile)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(existing))
+	for i, s := range existing {
+		index[s.ID()] = i
+	}
+	for _, s := range suggestions {
+		if i, ok := index[s.ID()]; ok {
+			existing[i] = s
+			continue
+		}
+		index[s.ID()] = len(existing)
+		existing = append(existing, s)
+	}
+
+	return r.save(profile, existing)
+}
+
+func (r *SuggestionRepository) ListSuggestions(ctx context.Context, profile string) ([]*entity.Suggestion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(profile)
+}
+
+func (r *SuggestionRepository) FindSuggestion(ctx context.Context, profile, id string) (*entity.Suggestion, error) {
+	suggestions, err := r.ListSuggestions(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range suggestions {
+		if s.ID() == id {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("suggestion %s: %w", id, domain.ErrNotFound)
+}
+
+func (r *SuggestionRepository) path(profile string) string {
+	return filepath.Join(r.basePath, profile, "suggestions.md")
+}
+
+func (r *SuggestionRepository) load(profile string) ([]*entity.Suggestion, error) {
+	var dtos []entity.SuggestionDTO
+	if err := readDocument(r.path(profile), &dtos); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	suggestions := make([]*entity.Suggestion, len(dtos))
+	for i := range dtos {
+		suggestions[i] = entity.SuggestionFromDTO(&dtos[i])
+	}
+	return suggestions, nil
+}
+
+func (r *SuggestionRepository) save(profile string, suggestions []*entity.Suggestion) error {
+	dtos := make([]*entity.SuggestionDTO, len(suggestions))
+	for i, s := range suggestions {
+		dtos[i] = s.ToDTO()
+	}
+	return writeDocument(r.path(profile), []string{
+		"profile: " + profile,
+		fmt.Sprintf("count: %d", len(dtos)),
+	}, dtos)
+}
@@ -3,20 +3,22 @@ package markdown
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"r3f-trends/internal/domain"
 	"r3f-trends/internal/domain/entity"
 )
 
-var ErrNotFound = errors.New("entity not found")
+var ErrNotFound = domain.ErrNotFound
 
 type TrendRepository struct {
 	basePath string
+	mu       sync.Mutex
 }
 
 func NewTrendRepository(basePath string) *TrendRepository {
@@ -44,11 +46,28 @@ func (r *TrendRepository) Save(ctx context.Context, trend *entity.Trend) error {
 	return r.SaveBatch(ctx, []*entity.Trend{trend})
 }
 
+// CheckWritable creates and removes a file where trends are stored.
+func (r *TrendRepository) CheckWritable(ctx context.Context) error {
+	profilePath := filepath.Join(r.basePath, "tech", "trends")
+	if err := os.MkdirAll(profilePath, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(profilePath, ".health-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
 func (r *TrendRepository) SaveBatch(ctx context.Context, trends []*entity.Trend) error {
 	if len(trends) == 0 {
 		return nil
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	profilePath := filepath.Join(r.basePath, "tech", "trends")
 	if err := os.MkdirAll(profilePath, 0755); err != nil {
 		return err
@@ -62,15 +81,7 @@ func (r *TrendRepository) SaveBatch(ctx context.Context, trends []*entity.Trend)
 		return err
 	}
 
-	trendMap := make(map[string]*entity.Trend)
-	for _, t := range existingTrends {
-		trendMap[t.ID()] = t
-	}
-	for _, t := range trends {
-		trendMap[t.ID()] = t
-	}
-
-	return r.saveToFile(filename, trendMap)
+	return r.saveToFile(filename, mergeTrends(existingTrends, trends))
 }
 
 func (r *TrendRepository) FindByID(ctx context.Context, id string) (*entity.Trend, error) {
@@ -189,10 +200,109 @@ func (r *TrendRepository) Search(ctx context.Context, query string, opts SearchO
 	return results[start:end], total, nil
 }
 
+// Update rewrites the day file that already holds the trend, so updating an
+// older trend does not copy it into today's file.
 func (r *TrendRepository) Update(ctx context.Context, trend *entity.Trend) error {
+	r.mu.Lock()
+	files, err := filepath.Glob(filepath.Join(r.basePath, "tech", "trends", "*.md"))
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+
+	for i := len(files) - 1; i >= 0; i-- {
+		trends, err := r.loadFromFile(files[i])
+		if err != nil {
+			continue
+		}
+		for _, t := range trends {
+			if t.ID() == trend.ID() {
+				err := r.saveToFile(files[i], mergeTrends(trends, []*entity.Trend{trend}))
+				r.mu.Unlock()
+				return err
+			}
+		}
+	}
+	r.mu.Unlock()
+
 	return r.Save(ctx, trend)
 }
 
+// MergeBatch stores trends from elsewhere, such as an export: a trend that
+// is already stored is replaced in every day file that holds it, and the
+// others are added to the file of the day they were collected. It returns
+// how many trends were added.
+func (r *TrendRepository) MergeBatch(ctx context.Context, trends []*entity.Trend) (int, error) {
+	if len(trends) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profilePath := filepath.Join(r.basePath, "tech", "trends")
+	if err := os.MkdirAll(profilePath, 0755); err != nil {
+		return 0, err
+	}
+	files, err := filepath.Glob(filepath.Join(profilePath, "*.md"))
+	if err != nil {
+		return 0, err
+	}
+
+	byID := make(map[string]*entity.Trend, len(trends))
+	for _, t := range trends {
+		byID[t.ID()] = t
+	}
+
+	stored := make(map[string]bool)
+	for _, file := range files {
+		existing, err := r.loadFromFile(file)
+		if err != nil {
+			continue
+		}
+		var updates []*entity.Trend
+		for _, t := range existing {
+			if update, ok := byID[t.ID()]; ok {
+				updates = append(updates, update)
+				stored[t.ID()] = true
+			}
+		}
+		if len(updates) > 0 {
+			if err := r.saveToFile(file, mergeTrends(existing, updates)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	byDate := make(map[string][]*entity.Trend)
+	var dates []string
+	for _, t := range trends {
+		if stored[t.ID()] {
+			continue
+		}
+		stored[t.ID()] = true
+		date := t.CollectedAt().Format("2006-01-02")
+		if _, ok := byDate[date]; !ok {
+			dates = append(dates, date)
+		}
+		byDate[date] = append(byDate[date], t)
+	}
+
+	added := 0
+	for _, date := range dates {
+		filename := filepath.Join(profilePath, fmt.Sprintf("%s.md", date))
+		existing, err := r.loadFromFile(filename)
+		if err != nil && !os.IsNotExist(err) {
+			return added, err
+		}
+		if err := r.saveToFile(filename, mergeTrends(existing, byDate[date])); err != nil {
+			return added, err
+		}
+		added += len(byDate[date])
+	}
+	return added, nil
+}
+
 func (r *TrendRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
@@ -254,8 +364,8 @@ func (r *TrendRepository) loadFromFile(filename string) ([]*entity.Trend, error)
 	return result, nil
 }
 
-func (r *TrendRepository) saveToFile(filename string, trends map[string]*entity.Trend) error {
-	var trendList []entity.TrendDTO
+func (r *TrendRepository) saveToFile(filename string, trends []*entity.Trend) error {
+	trendList := make([]entity.TrendDTO, 0, len(trends))
 	for _, t := range trends {
 		trendList = append(trendList, *t.ToDTO())
 	}
@@ -265,12 +375,14 @@ func (r *TrendRepository) saveToFile(filename string, trends map[string]*entity.
 		return err
 	}
 
+	date := strings.TrimSuffix(filepath.Base(filename), ".md")
+
 	frontmatter := fmt.Sprintf(`---
 date: %s
 count: %d
 ---
 
-`, time.Now().Format("2006-01-02"), len(trendList))
+`, date, len(trendList))
 
 	var mdContent strings.Builder
 	mdContent.WriteString(frontmatter)
@@ -280,3 +392,21 @@ count: %d
 
 	return os.WriteFile(filename, []byte(mdContent.String()), 0644)
 }
+
+// mergeTrends replaces existing trends by ID and appends new ones, keeping
+// the file order stable across writes.
+func mergeTrends(existing, updates []*entity.Trend) []*entity.Trend {
+	index := make(map[string]int, len(existing)+len(updates))
+	merged := make([]*entity.Trend, 0, len(existing)+len(updates))
+	for _, batch := range [][]*entity.Trend{existing, updates} {
+		for _, t := range batch {
+			if i, ok := index[t.ID()]; ok {
+				merged[i] = t
+				continue
+			}
+			index[t.ID()] = len(merged)
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
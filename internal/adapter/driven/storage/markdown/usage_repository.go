@@ -0,0 +1,92 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"r3f-trends/internal/app/service"
+)
+
+// UsageRepository keeps one file of aggregated LLM usage per day under
+// <base>/usage.
+type UsageRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+func NewUsageRepository(basePath string) *UsageRepository {
+	return &UsageRepository{basePath: basePath}
+}
+
+func (r *UsageRepository) AddUsage(ctx context.Context, rec service.UsageRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filename := r.path(rec.Date)
+	records, err := r.load(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	merged := false
+	for i := range records {
+		if records[i].SameBucket(rec) {
+			records[i].Add(rec)
+			merged = true
+			break
+		}
+	}
+	if !merged {
+		records = append(records, rec)
+	}
+
+	return writeDocument(filename, []string{
+		"date: " + rec.Date,
+		fmt.Sprintf("count: %d", len(records)),
+	}, records)
+}
+
+// ListUsage returns the records for dates between from and to inclusive
+// (YYYY-MM-DD); empty bounds are open.
+func (r *UsageRepository) ListUsage(ctx context.Context, from, to string) ([]service.UsageRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(r.basePath, "usage", "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var result []service.UsageRecord
+	for _, file := range files {
+		date := strings.TrimSuffix(filepath.Base(file), ".md")
+		if (from != "" && date < from) || (to != "" && date > to) {
+			continue
+		}
+		records, err := r.load(file)
+		if err != nil {
+			continue
+		}
+		result = append(result, records...)
+	}
+
+	return result, nil
+}
+
+func (r *UsageRepository) path(date string) string {
+	return filepath.Join(r.basePath, "usage", date+".md")
+}
+
+func (r *UsageRepository) load(filename string) ([]service.UsageRecord, error) {
+	var records []service.UsageRecord
+	if err := readDocument(filename, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
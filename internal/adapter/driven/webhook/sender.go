@@ -0,0 +1,136 @@
+// Package webhook delivers notifications to webhook sinks: generic JSON,
+// Slack and Discord incoming webhooks, and ntfy-style plain text topics.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256, keyed with
+	// the sink's secret, of the timestamp header, a dot and the body.
+	SignatureHeader = "X-R3F-Signature"
+	TimestampHeader = "X-R3F-Timestamp"
+	EventHeader     = "X-R3F-Event"
+
+	// discordLimit is the longest message, in characters, Discord accepts.
+	discordLimit = 2000
+)
+
+// Sender posts notifications over HTTP.
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, sink service.WebhookSink, n entity.Notification) error {
+	body, contentType, err := encode(sink.Format, n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(EventHeader, n.Event)
+
+	if sink.Format == service.WebhookNtfy {
+		req.Header.Set("Title", n.Title)
+		if n.URL != "" {
+			req.Header.Set("Click", n.URL)
+		}
+	}
+
+	if sink.Secret != "" {
+		ts := strconv.FormatInt(s.now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(sink.Secret, ts, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 300 {
+		return &service.WebhookStatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of timestamp + "." + body. Receivers
+// should compute the same and reject stale timestamps.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func encode(format string, n entity.Notification) ([]byte, string, error) {
+	switch format {
+	case service.WebhookJSON, "":
+		body, err := json.Marshal(n)
+		return body, "application/json", err
+	case service.WebhookSlack:
+		body, err := json.Marshal(map[string]string{"text": message(n, "*%s*")})
+		return body, "application/json", err
+	case service.WebhookDiscord:
+		text := message(n, "**%s**")
+		if runes := []rune(text); len(runes) > discordLimit {
+			text = string(runes[:discordLimit-1]) + "…"
+		}
+		body, err := json.Marshal(map[string]string{"content": text})
+		return body, "application/json", err
+	case service.WebhookNtfy:
+		// The title and link go in headers.
+		text := n.Text
+		if text == "" {
+			text = n.Title
+		}
+		return []byte(text), "text/plain; charset=utf-8", nil
+	}
+	return nil, "", fmt.Errorf("unknown webhook format %q", format)
+}
+
+// message renders n as chat text with the title in bold, using the
+// platform's bold markup.
+func message(n entity.Notification, bold string) string {
+	lines := []string{fmt.Sprintf(bold, n.Title)}
+	if n.Text != "" {
+		lines = append(lines, n.Text)
+	}
+	if n.URL != "" {
+		lines = append(lines, n.URL)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestSendSignsJSON(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header
+	}))
+	defer srv.Close()
+
+	sender := NewSender(time.Second)
+	sender.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	n := entity.Notification{Event: "alert.raised", Title: "Watch alert: go-release", Text: "Go 1.26 is released"}
+	sink := service.WebhookSink{Name: "hook", URL: srv.URL, Format: service.WebhookJSON, Secret: "s3cret"}
+	if err := sender.Send(context.Background(), sink, n); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var got entity.Notification
+	if err := json.Unmarshal(body, &got); err != nil || got.Text != n.Text {
+		t.Errorf("body = %s (%v)", body, err)
+	}
+	if headers.Get(TimestampHeader) != "1700000000" || headers.Get(EventHeader) != "alert.raised" {
+		t.Errorf("headers = %v", headers)
+	}
+	if want := "sha256=" + Sign("s3cret", "1700000000", body); headers.Get(SignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", headers.Get(SignatureHeader), want)
+	}
+}
+
+func TestSendFormats(t *testing.T) {
+	n := entity.Notification{Title: "Collected 3 trends", Text: "1 new trends for profile tech.", URL: "https://example.com"}
+
+	body, _, _ := encode(service.WebhookSlack, n)
+	if string(body) != `{"text":"*Collected 3 trends*\n1 new trends for profile tech.\nhttps://example.com"}` {
+		t.Errorf("slack = %s", body)
+	}
+	body, _, _ = encode(service.WebhookDiscord, n)
+	if string(body) != `{"content":"**Collected 3 trends**\n1 new trends for profile tech.\nhttps://example.com"}` {
+		t.Errorf("discord = %s", body)
+	}
+	body, contentType, _ := encode(service.WebhookNtfy, n)
+	if string(body) != n.Text || contentType != "text/plain; charset=utf-8" {
+		t.Errorf("ntfy = %s (%s)", body, contentType)
+	}
+}
+
+func TestSendStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := NewSender(time.Second).Send(context.Background(), service.WebhookSink{URL: srv.URL}, entity.Notification{})
+	var status *service.WebhookStatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v", err)
+	}
+}
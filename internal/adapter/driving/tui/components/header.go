@@ -1,6 +1,8 @@
 package components
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
 
 	"r3f-trends/internal/adapter/driving/tui/styles"
@@ -9,6 +11,7 @@ import (
 type Header struct {
 	width  int
 	status string
+	alerts int
 }
 
 func NewHeader() *Header {
@@ -25,9 +28,18 @@ func (h *Header) SetStatus(status string) {
 	h.status = status
 }
 
+// SetAlerts sets the number of unseen watch alerts shown as a badge.
+func (h *Header) SetAlerts(n int) {
+	h.alerts = n
+}
+
 func (h *Header) View() string {
 	title := styles.TitleStyle.Render("◈ R3F TREND COLLECTOR")
 	statusText := styles.TrendMetaStyle.Render(h.status)
+	if h.alerts > 0 {
+		badge := styles.StatusPendingStyle.Render(fmt.Sprintf("🔔 %d", h.alerts))
+		statusText = lipgloss.JoinHorizontal(lipgloss.Center, badge, "  ", statusText)
+	}
 	spacer := lipgloss.NewStyle().Width(h.width - lipgloss.Width(title) - lipgloss.Width(statusText) - 4).Render(" ")
 
 	return styles.HeaderStyle.Width(h.width).Render(
@@ -22,7 +22,7 @@ func NewFooter() *Footer {
 		lastRun:  "Never",
 		count:    0,
 		starred:  0,
-		helpText: "[c] Collect  [s] Star  [↑↓] Navigate  [q] Quit",
+		helpText: "[c] Collect  [s] Star  [g] Group  [a] Alerts seen  [↑↓] Navigate  [q] Quit",
 	}
 }
 
@@ -23,6 +23,14 @@ type TrendItem struct {
 	Author    string
 	Timestamp time.Time
 	Starred   bool
+	// LLMSummary is the latest stored model summary, LLMSummaryBy says
+	// which provider/model wrote it and when.
+	LLMSummary   string
+	LLMSummaryBy string
+	// Cluster names the storyline the trend belongs to when the list is
+	// grouped by cluster.
+	Cluster     string
+	CollectedAt time.Time
 }
 
 func (t TrendItem) FilterValue() string {
@@ -48,6 +56,9 @@ func (d trendDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 	}
 
 	meta := fmt.Sprintf("%s • %d pts", t.Source, t.Score)
+	if t.Cluster != "" {
+		meta = styles.TrendSourceStyle.Render("◆ "+t.Cluster) + " • " + meta
+	}
 	if t.Author != "" {
 		meta += fmt.Sprintf(" • @%s", t.Author)
 	}
@@ -107,6 +118,10 @@ func (t *TrendsList) SetTrends(trends []TrendItem) {
 	t.list.SetItems(items)
 }
 
+func (t *TrendsList) SetTitle(title string) {
+	t.list.Title = title
+}
+
 func (t *TrendsList) SelectedTrend() *TrendItem {
 	if item, ok := t.list.SelectedItem().(TrendItem); ok {
 		return &item
@@ -171,6 +186,13 @@ func (d *DetailView) View() string {
 		b.WriteString(d.trend.Summary)
 	}
 
+	if d.trend.LLMSummary != "" {
+		b.WriteString("\n\n")
+		b.WriteString(styles.TrendMetaStyle.Render("Summary (" + d.trend.LLMSummaryBy + ")"))
+		b.WriteString("\n")
+		b.WriteString(d.trend.LLMSummary)
+	}
+
 	b.WriteString("\n\n")
 	url := styles.TrendSourceStyle.Render(d.trend.URL)
 	b.WriteString(url)
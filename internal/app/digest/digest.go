@@ -0,0 +1,150 @@
+// Package digest renders trend digests from Go templates, as Markdown with
+// text/template and as HTML with html/template.
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultSubject is used when digest.subject is not set.
+const DefaultSubject = `{{.Profile.DisplayName}} digest for {{.Period}}`
+
+// DefaultMarkdown is used when digest.templates.markdown is not set.
+const DefaultMarkdown = `# {{.Profile.DisplayName}} digest, {{.Period}}
+
+{{len .Trends}} top stories of {{.Total}} collected.
+{{range .Trends}}
+## {{if .Starred}}★ {{end}}[{{.Title}}]({{.URL}})
+
+{{.Source}}, {{.Score}} points{{with .Storyline}} · {{.}}{{end}}{{if .Related}} · {{.Related}} related{{end}}
+{{with .Summary}}
+{{.}}
+{{end}}{{end}}`
+
+// DefaultHTML is used when digest.templates.html is not set.
+const DefaultHTML = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Profile.DisplayName}} digest, {{.Period}}</title></head>
+<body style="font-family: sans-serif; max-width: 40em; margin: auto;">
+<h1>{{.Profile.DisplayName}} digest, {{.Period}}</h1>
+<p>{{len .Trends}} top stories of {{.Total}} collected.</p>
+{{range .Trends}}
+<h2>{{if .Starred}}★ {{end}}<a href="{{.URL}}">{{.Title}}</a></h2>
+<p style="color: #6B7280;">{{.Source}}, {{.Score}} points{{with .Storyline}} · {{.}}{{end}}{{if .Related}} · {{.Related}} related{{end}}</p>
+{{with .Summary}}<p>{{.}}</p>{{end}}
+{{end}}
+</body>
+</html>
+`
+
+// Data is the set of variables available to digest templates:
+//
+//	.Profile.Name, .Profile.DisplayName, .Profile.Description
+//	.From, .To  the period covered (YYYY-MM-DD)
+//	.Period     "From" for a single day, "From to To" otherwise
+//	.Total      how many trends were collected in the period
+//	.Trends     the selected trends with .ID, .Title, .URL, .Source,
+//	            .Score, .Starred, .Summary, .Tags, .Storyline (the name of
+//	            its cluster) and .Related (other trends of that cluster)
+type Data struct {
+	Profile Profile
+	From    string
+	To      string
+	Period  string
+	Total   int
+	Trends  []Trend
+}
+
+type Profile struct {
+	Name        string
+	DisplayName string
+	Description string
+}
+
+type Trend struct {
+	ID        string
+	Title     string
+	URL       string
+	Source    string
+	Score     int
+	Starred   bool
+	Summary   string
+	Tags      []string
+	Storyline string
+	Related   int
+}
+
+// Period describes from..to for headings.
+func Period(from, to string) string {
+	if from == to {
+		return from
+	}
+	return from + " to " + to
+}
+
+// RenderText executes text, or fallback if text is empty, as a
+// text/template. It is used for the Markdown body and the subject.
+func RenderText(text, fallback string, data Data) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	tmpl, err := texttemplate.New("digest").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse digest template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenderHTML executes text, or fallback if text is empty, as an
+// html/template, so trend titles and summaries are escaped.
+func RenderHTML(text, fallback string, data Data) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	tmpl, err := htmltemplate.New("digest").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse digest template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Validate renders the templates against sample data so that mistakes are
+// reported at startup rather than when the first digest is due.
+func Validate(subject, markdown, html string) error {
+	sample := Data{
+		Profile: Profile{Name: "sample", DisplayName: "Sample"},
+		From:    "2006-01-02",
+		To:      "2006-01-02",
+		Period:  "2006-01-02",
+		Total:   1,
+		Trends: []Trend{{
+			ID: "sample-1", Title: "Sample", URL: "https://example.com", Source: "Sample",
+			Score: 1, Summary: "Sample", Tags: []string{"sample"}, Storyline: "Sample", Related: 1,
+		}},
+	}
+
+	if _, err := RenderText(subject, DefaultSubject, sample); err != nil {
+		return fmt.Errorf("subject: %w", err)
+	}
+	if _, err := RenderText(markdown, DefaultMarkdown, sample); err != nil {
+		return fmt.Errorf("markdown: %w", err)
+	}
+	if _, err := RenderHTML(html, DefaultHTML, sample); err != nil {
+		return fmt.Errorf("html: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package exchange
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"r3f-trends/internal/domain"
+)
+
+// OPMLContentType is the media type of OPML documents.
+const OPMLContentType = "text/x-opml; charset=utf-8"
+
+// Subscription is a feed as listed in an OPML file.
+type Subscription struct {
+	Title   string
+	FeedURL string
+	SiteURL string
+}
+
+type opmlDoc struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Head    opmlHead      `xml:"head"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// WriteOPML writes subs as an OPML 2.0 subscription list.
+func WriteOPML(w io.Writer, title string, subs []Subscription) error {
+	doc := opmlDoc{
+		Version: "2.0",
+		Head:    opmlHead{Title: title, DateCreated: time.Now().UTC().Format(time.RFC1123Z)},
+	}
+	for _, sub := range subs {
+		doc.Body = append(doc.Body, opmlOutline{
+			Text:    sub.Title,
+			Title:   sub.Title,
+			Type:    "rss",
+			XMLURL:  sub.FeedURL,
+			HTMLURL: sub.SiteURL,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadOPML returns the feeds of an OPML file. Outlines without a feed URL,
+// such as folders, are skipped, but the feeds inside them are read.
+func ReadOPML(r io.Reader) ([]Subscription, error) {
+	var doc opmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: not an OPML file: %v", domain.ErrInvalidRequest, err)
+	}
+
+	var subs []Subscription
+	var walk func(outlines []opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, o := range outlines {
+			if o.XMLURL != "" {
+				title := o.Title
+				if title == "" {
+					title = o.Text
+				}
+				if title == "" {
+					title = o.XMLURL
+				}
+				subs = append(subs, Subscription{Title: title, FeedURL: o.XMLURL, SiteURL: o.HTMLURL})
+			}
+			walk(o.Outlines)
+		}
+	}
+	walk(doc.Body)
+	return subs, nil
+}
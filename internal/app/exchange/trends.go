@@ -0,0 +1,223 @@
+// Package exchange reads and writes trends and feed subscriptions in formats
+// other tools understand: JSON Lines and CSV for trends, OPML for feeds.
+package exchange
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+// Trend formats.
+const (
+	JSONL = "jsonl"
+	CSV   = "csv"
+)
+
+// maxLine is the longest JSON Lines record read, enough for a trend with
+// many stored summaries.
+const maxLine = 4 << 20
+
+// csvHeader lists the CSV columns. CSV leaves out metadata and stored
+// summaries; JSON Lines keeps the whole trend.
+var csvHeader = []string{
+	"id", "title", "url", "summary", "score", "author", "source", "source_id",
+	"category", "tags", "timestamp", "collected_at", "starred",
+}
+
+// ContentType is the media type of format.
+func ContentType(format string) string {
+	if format == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Encoder writes trends one at a time, so an export can be streamed.
+type Encoder interface {
+	Encode(t *entity.TrendDTO) error
+	// Flush writes anything buffered.
+	Flush() error
+}
+
+// NewEncoder returns an encoder for format writing to w.
+func NewEncoder(format string, w io.Writer) (Encoder, error) {
+	switch format {
+	case JSONL, "":
+		return &jsonlEncoder{enc: json.NewEncoder(w)}, nil
+	case CSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown format %q, want jsonl or csv", domain.ErrInvalidRequest, format)
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(t *entity.TrendDTO) error { return e.enc.Encode(t) }
+func (e *jsonlEncoder) Flush() error                    { return nil }
+
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) Encode(t *entity.TrendDTO) error {
+	if !e.header {
+		e.header = true
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	return e.w.Write([]string{
+		t.ID, t.Title, t.URL, t.Summary, strconv.Itoa(t.Score), t.Author, t.Source, t.SourceID,
+		t.Category, strings.Join(t.Tags, ";"), formatTime(t.Timestamp), formatTime(t.CollectedAt),
+		strconv.FormatBool(t.Starred),
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Decode reads trends in format from r and calls fn for each. Errors name
+// the line they were found on and wrap domain.ErrInvalidRequest.
+func Decode(format string, r io.Reader, fn func(t *entity.TrendDTO) error) error {
+	switch format {
+	case JSONL, "":
+		return decodeJSONL(r, fn)
+	case CSV:
+		return decodeCSV(r, fn)
+	}
+	return fmt.Errorf("%w: unknown format %q, want jsonl or csv", domain.ErrInvalidRequest, format)
+}
+
+func decodeJSONL(r io.Reader, fn func(t *entity.TrendDTO) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var dto entity.TrendDTO
+		if err := json.Unmarshal(scanner.Bytes(), &dto); err != nil {
+			return fmt.Errorf("%w: line %d: %v", domain.ErrInvalidRequest, line, err)
+		}
+		if err := validate(&dto, line); err != nil {
+			return err
+		}
+		if err := fn(&dto); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: line %d: %v", domain.ErrInvalidRequest, line+1, err)
+	}
+	return nil
+}
+
+func decodeCSV(r io.Reader, fn func(t *entity.TrendDTO) error) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+	column := make(map[string]int, len(header))
+	for i, name := range header {
+		column[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"id", "title"} {
+		if _, ok := column[required]; !ok {
+			return fmt.Errorf("%w: line 1: no %s column", domain.ErrInvalidRequest, required)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := column[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		dto := entity.TrendDTO{
+			ID:       field("id"),
+			Title:    field("title"),
+			URL:      field("url"),
+			Summary:  field("summary"),
+			Author:   field("author"),
+			Source:   field("source"),
+			SourceID: field("source_id"),
+			Category: field("category"),
+		}
+		if v := field("score"); v != "" {
+			if dto.Score, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("%w: line %d: score: %v", domain.ErrInvalidRequest, line, err)
+			}
+		}
+		if v := field("tags"); v != "" {
+			dto.Tags = strings.Split(v, ";")
+		}
+		if dto.Timestamp, err = parseTime(field("timestamp")); err != nil {
+			return fmt.Errorf("%w: line %d: timestamp: %v", domain.ErrInvalidRequest, line, err)
+		}
+		if dto.CollectedAt, err = parseTime(field("collected_at")); err != nil {
+			return fmt.Errorf("%w: line %d: collected_at: %v", domain.ErrInvalidRequest, line, err)
+		}
+		dto.Starred = field("starred") == "true"
+
+		if err := validate(&dto, line); err != nil {
+			return err
+		}
+		if err := fn(&dto); err != nil {
+			return err
+		}
+	}
+}
+
+func validate(dto *entity.TrendDTO, line int) error {
+	switch {
+	case dto.ID == "":
+		return fmt.Errorf("%w: line %d: trend has no id", domain.ErrInvalidRequest, line)
+	case dto.Title == "":
+		return fmt.Errorf("%w: line %d: trend %s has no title", domain.ErrInvalidRequest, line, dto.ID)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,268 @@
+// Package feed renders trends as Atom 1.0, RSS 2.0 and JSON Feed 1.1
+// documents for feed readers.
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// Content types of the rendered formats.
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	JSONContentType = "application/feed+json; charset=utf-8"
+)
+
+// Feed is a format-neutral feed. Link is the page readers open and Self the
+// URL the feed was fetched from.
+type Feed struct {
+	ID          string
+	Title       string
+	Description string
+	Link        string
+	Self        string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is one trend. ID is a stable GUID, so readers do not show a trend
+// again when its score or summary changes.
+type Item struct {
+	ID        string
+	Title     string
+	URL       string
+	Content   string
+	Author    string
+	Source    string
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+// GUID is the item ID of a trend.
+func GUID(trendID string) string {
+	return "urn:r3f-trends:trend:" + trendID
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Sub     string      `xml:"subtitle,omitempty"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomPerson    `xml:"author"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    *atomText      `xml:"content"`
+}
+
+// Atom renders f as an Atom 1.0 document.
+func Atom(f *Feed) ([]byte, error) {
+	doc := atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Sub:     f.Description,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: f.Title},
+	}
+	if f.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "alternate", Href: f.Link})
+	}
+	if f.Self != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "self", Type: "application/atom+xml", Href: f.Self})
+	}
+
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: item.Updated.UTC().Format(time.RFC3339),
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.UTC().Format(time.RFC3339)
+		}
+		if author := itemAuthor(item); author != "" {
+			entry.Author = &atomPerson{Name: author}
+		}
+		if item.URL != "" {
+			entry.Links = []atomLink{{Rel: "alternate", Href: item.URL}}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "text", Body: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          *atomLink `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+// RSS renders f as an RSS 2.0 document. GUIDs are not permalinks, since
+// trend URLs may change or be shared by several trends.
+func RSS(f *Feed) ([]byte, error) {
+	doc := rssDoc{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+	if doc.Channel.Description == "" {
+		doc.Channel.Description = f.Title
+	}
+	if f.Self != "" {
+		doc.Channel.Self = &atomLink{Rel: "self", Type: "application/rss+xml", Href: f.Self}
+	}
+
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			Description: item.Content,
+			Categories:  item.Tags,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     itemPublished(item).UTC().Format(time.RFC1123Z),
+		})
+	}
+	return marshalXML(doc)
+}
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url,omitempty"`
+	FeedURL     string     `json:"feed_url,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url,omitempty"`
+	Title         string       `json:"title"`
+	ContentText   string       `json:"content_text"`
+	DatePublished string       `json:"date_published,omitempty"`
+	DateModified  string       `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+// JSON renders f as a JSON Feed 1.1 document.
+func JSON(f *Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.Self,
+		Description: f.Description,
+		Items:       []jsonItem{},
+	}
+	for _, item := range f.Items {
+		ji := jsonItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentText:   item.Content,
+			DatePublished: itemPublished(item).UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		if author := itemAuthor(item); author != "" {
+			ji.Authors = []jsonAuthor{{Name: author}}
+		}
+		doc.Items = append(doc.Items, ji)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func itemPublished(item Item) time.Time {
+	if item.Published.IsZero() {
+		return item.Updated
+	}
+	return item.Published
+}
+
+// itemAuthor names the author, or the source for trends without one.
+func itemAuthor(item Item) string {
+	if item.Author != "" {
+		return item.Author
+	}
+	return item.Source
+}
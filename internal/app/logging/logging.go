@@ -0,0 +1,96 @@
+// Package logging sets up log/slog from the logging config and carries log
+// attributes, such as the request ID, in contexts so that everything done
+// for one request or collection job can be traced.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// New returns a logger writing to w. level is debug, info (the default),
+// warn or error; format is json or text (the default).
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid logging.level %q", level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid logging.format %q: want json or text", format)
+	}
+	return slog.New(&contextHandler{Handler: h}), nil
+}
+
+type attrsKey struct{}
+
+// With returns a context whose log records carry args, given as for
+// slog.Logger.With. Records must be logged with the *Context functions,
+// e.g. slog.InfoContext, for the attributes to show.
+func With(ctx context.Context, args ...any) context.Context {
+	prev, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	r := slog.Record{}
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, len(prev)+r.NumAttrs())
+	attrs = append(attrs, prev...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID tags ctx and its log records with a request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return With(context.WithValue(ctx, requestIDKey{}, id), "request_id", id)
+}
+
+// RequestID returns the request ID of ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random ID for a request that did not bring one.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the attributes from With to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
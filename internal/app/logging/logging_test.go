@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = With(ctx, "job_id", "tech-1")
+	logger.With("component", "collector").InfoContext(With(ctx, "source", "hn"), "source collected", "items", 3)
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("not JSON: %s", buf.String())
+	}
+	want := map[string]any{
+		"msg": "source collected", "level": "INFO", "request_id": "req-1", "job_id": "tech-1",
+		"source": "hn", "items": float64(3), "component": "collector",
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%s = %v, want %v", k, rec[k], v)
+		}
+	}
+	if RequestID(ctx) != "req-1" {
+		t.Errorf("RequestID = %q", RequestID(ctx))
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := New(&buf, "loud", "text"); err == nil {
+		t.Error("level loud accepted")
+	}
+	if _, err := New(&buf, "info", "xml"); err == nil {
+		t.Error("format xml accepted")
+	}
+
+	logger, err := New(&buf, "warn", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("info logged at warn level: %s", buf.String())
+	}
+}
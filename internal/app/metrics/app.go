@@ -0,0 +1,27 @@
+package metrics
+
+// The application's metrics, all in Default.
+var (
+	HTTPRequests = Default.Counter("r3f_http_requests_total",
+		"HTTP requests by route pattern, method and status code.", "route", "method", "status")
+	HTTPDuration = Default.Histogram("r3f_http_request_duration_seconds",
+		"Time to answer HTTP requests, by route pattern and method.", nil, "route", "method")
+
+	CollectionDuration = Default.Histogram("r3f_collection_duration_seconds",
+		"Time to collect one source.", []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120}, "source")
+	ItemsCollected = Default.Counter("r3f_collected_items_total",
+		"Trends returned by collectors, by source.", "source")
+	CollectorErrors = Default.Counter("r3f_collector_errors_total",
+		"Failed source collections, by source and error type.", "source", "type")
+	LastCollection = Default.Gauge("r3f_collection_last_success_timestamp_seconds",
+		"Unix time of the last successful collection of a source.", "source")
+
+	LLMDuration = Default.Histogram("r3f_llm_request_duration_seconds",
+		"Time for LLM provider calls, including retries, by outcome.",
+		[]float64{.25, .5, 1, 2.5, 5, 10, 20, 30, 60, 120}, "provider", "operation", "outcome")
+	LLMTokens = Default.Counter("r3f_llm_tokens_total",
+		"Tokens used by LLM calls; kind is prompt or completion.", "provider", "model", "kind")
+
+	StorageDuration = Default.Histogram("r3f_storage_operation_duration_seconds",
+		"Time for trend storage operations.", []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}, "operation")
+)
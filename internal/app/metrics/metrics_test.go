@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteTextFormat(t *testing.T) {
+	r := NewRegistry()
+	requests := r.Counter("requests_total", "Requests.", "route")
+	latency := r.Histogram("latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+	up := r.Gauge("up", "Up.")
+
+	requests.Inc(`/a"b`)
+	requests.Add(2, `/a"b`)
+	latency.Observe(0.5, "/a")
+	latency.Observe(5, "/a")
+	up.Set(1)
+
+	var out strings.Builder
+	if err := r.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{route="/a\"b"} 3
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 0
+latency_seconds_bucket{route="/a",le="1"} 1
+latency_seconds_bucket{route="/a",le="+Inf"} 2
+latency_seconds_sum{route="/a"} 5.5
+latency_seconds_count{route="/a"} 2
+# HELP up Up.
+# TYPE up gauge
+up 1
+`
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,224 @@
+package prompt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// DefaultSummarizer is used when a profile does not define prompts.summarizer.
+const DefaultSummarizer = `Summarize the following content in 2-3 sentences, highlighting key technical insights:
+
+{{.Content}}
+
+Provide a concise summary:`
+
+// DefaultSuggester is used when a profile does not define prompts.suggester.
+const DefaultSuggester = `Based on these trending topics, suggest 3 blog post ideas{{with .Profile.Description}} for a blog about {{.}}{{end}}.
+
+Topics:
+{{range .Trends}}- [{{.ID}}] {{.Title}}
+{{end}}{{with .Previous}}
+These ideas were suggested before; do not repeat or rephrase them:
+{{range .}}* {{.Title}} ({{.Status}})
+{{end}}{{end}}
+For each suggestion, give a title, a brief description, a score between 0 and 1,
+and the IDs of the topics it is based on, as JSON:
+{"suggestions": [{"title": "...", "description": "...", "score": 0.9, "topics": ["..."]}]}`
+
+// DefaultTagger is used when a profile does not define prompts.tagger.
+const DefaultTagger = `Classify the following article for a blog{{with .Profile.Description}} about {{.}}{{end}}.
+
+{{range .Trends}}Title: {{.Title}}
+Source: {{.Source}}
+{{end}}
+{{.Content}}
+
+Respond with JSON only, using lowercase tags of one or two words:
+{"category": "...", "tags": ["...", "..."]}`
+
+// DefaultOutliner is used when a profile does not define prompts.outliner.
+const DefaultOutliner = `Plan a blog post{{with .Profile.Description}} for a blog about {{.}}{{end}}{{with .Idea.Title}} titled "{{.}}"{{end}}.
+{{with .Idea.Description}}
+{{.}}
+{{end}}
+Sources:
+{{range .Trends}}
+[{{.ID}}] {{.Title}}
+URL: {{.URL}}
+{{.Content}}
+{{end}}
+Give the post a title and 3 to 6 sections, each with a heading, its key
+points and the IDs of the sources it draws on, as JSON:
+{"title": "...", "sections": [{"heading": "...", "points": ["..."], "sources": ["..."]}]}`
+
+// DefaultDrafter is used when a profile does not define prompts.drafter.
+const DefaultDrafter = `Write a blog post in markdown{{with .Profile.Description}} for a blog about {{.}}{{end}}, following this outline:
+
+{{.Outline}}
+Sources:
+{{range .Trends}}
+[{{.Ref}}] {{.Title}} ({{.URL}})
+{{.Content}}
+{{end}}
+Start with a "# " title line and use "## " section headings. Cite sources
+inline by number, like [1]. Do not add a list of sources at the end.`
+
+// DefaultNamer is used when a profile does not define prompts.namer.
+const DefaultNamer = `These headlines are about the same story:
+
+{{range .Trends}}- {{.Title}}
+{{end}}
+Name the story in at most six words. Reply with the name only.`
+
+// Data is the set of variables available to prompt templates:
+//
+//	.Profile.Name, .Profile.DisplayName, .Profile.Description
+//	.Date     collection date (YYYY-MM-DD)
+//	.Content  text to summarise (summarizer)
+//	.Topics   trend titles, one per line (suggester)
+//	.Trends   trends with .ID, .Title, .URL, .Source, .Score and .Summary;
+//	          for the outliner and drafter also .Ref, the citation number,
+//	          and .Content, the summary and article text
+//	.Previous recent suggestions with .Title and .Status (new,
+//	          accepted, rejected or written; suggester)
+//	.Idea     the suggestion a post is written from, with .Title and
+//	          .Description (outliner; empty when drafting from trends)
+//	.Outline  the outline as markdown (drafter)
+//
+// The legacy placeholders {{content}} and {{topics}} are still accepted and
+// expand to .Content and .Topics.
+type Data struct {
+	Profile  Profile
+	Date     string
+	Content  string
+	Topics   string
+	Trends   []Trend
+	Previous []Suggestion
+	Idea     Suggestion
+	Outline  string
+}
+
+type Suggestion struct {
+	Title       string
+	Description string
+	Status      string
+}
+
+type Profile struct {
+	Name        string
+	DisplayName string
+	Description string
+}
+
+type Trend struct {
+	ID      string
+	Title   string
+	URL     string
+	Source  string
+	Score   int
+	Summary string
+	Ref     int
+	Content string
+}
+
+func NewData(profile *entity.Profile, trends []*entity.Trend) Data {
+	d := Data{
+		Date: time.Now().Format("2006-01-02"),
+	}
+	if profile != nil {
+		d.Profile = Profile{
+			Name:        profile.Name(),
+			DisplayName: profile.DisplayName(),
+			Description: profile.Description(),
+		}
+	}
+
+	titles := make([]string, 0, len(trends))
+	for i, t := range trends {
+		d.Trends = append(d.Trends, Trend{
+			Ref:     i + 1,
+			ID:      t.ID(),
+			Title:   t.Title(),
+			URL:     t.URL(),
+			Source:  t.Source(),
+			Score:   t.Score(),
+			Summary: t.Summary(),
+		})
+		titles = append(titles, t.Title())
+	}
+	d.Topics = strings.Join(titles, "\n")
+
+	return d
+}
+
+// Version identifies the template that Render would use for text, so stored
+// LLM output can be matched to the prompt that produced it.
+func Version(text, fallback string) string {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Render executes text as a template against data. An empty text renders
+// fallback instead.
+func Render(text, fallback string, data Data) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+
+	tmpl, err := parse(text, data)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render prompt: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// Validate parses text and executes it against sample data so that unknown
+// variables are reported when the profile is loaded rather than on first use.
+func Validate(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	_, err := Render(text, "", Data{
+		Profile:  Profile{Name: "sample"},
+		Date:     "2006-01-02",
+		Content:  "sample",
+		Topics:   "sample",
+		Trends:   []Trend{{ID: "sample", Title: "sample", Ref: 1, Content: "sample"}},
+		Previous: []Suggestion{{Title: "sample", Status: "accepted"}},
+		Idea:     Suggestion{Title: "sample", Description: "sample"},
+		Outline:  "sample",
+	})
+	return err
+}
+
+func parse(text string, data Data) (*template.Template, error) {
+	tmpl, err := template.New("prompt").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"content": func() string { return data.Content },
+			"topics":  func() string { return data.Topics },
+			"join":    strings.Join,
+		}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt: %w", err)
+	}
+	return tmpl, nil
+}
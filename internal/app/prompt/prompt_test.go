@@ -0,0 +1,76 @@
+package prompt_test
+
+import (
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/app/prompt"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestRender(t *testing.T) {
+	data := prompt.NewData(nil, []*entity.Trend{
+		entity.NewTrend("hn-1", "Go 1.26 released", "https://go.dev/blog/go1.26"),
+		entity.NewTrend("gh-1", "golang/go", ""),
+	})
+	data.Content = "The article."
+
+	tests := []struct {
+		name, text, want string
+	}{
+		{"fields", "{{range .Trends}}[{{.Ref}}] {{.ID}} {{end}}", "[1] hn-1 [2] gh-1 "},
+		{"legacy content", "Summarize: {{content}}", "Summarize: The article."},
+		{"legacy topics", "Topics:\n{{topics}}", "Topics:\nGo 1.26 released\ngolang/go"},
+		{"fallback for blank text", "  \n", "Summarize: The article."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prompt.Render(tt.text, "Summarize: {{.Content}}", data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRejectsUnknownVariables(t *testing.T) {
+	for _, text := range []string{"{{.Nope}}", "{{.Profile.Owner}}", "{{range .Trends}}{{.Author}}{{end}}"} {
+		if _, err := prompt.Render(text, "", prompt.Data{Trends: []prompt.Trend{{ID: "hn-1"}}}); err == nil {
+			t.Errorf("Render(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, text := range []string{"", prompt.DefaultSummarizer, prompt.DefaultSuggester, prompt.DefaultTagger,
+		prompt.DefaultOutliner, prompt.DefaultDrafter, prompt.DefaultNamer, "{{content}} {{topics}}"} {
+		if err := prompt.Validate(text); err != nil {
+			t.Errorf("Validate(%.30q) = %v", text, err)
+		}
+	}
+
+	tests := map[string]string{
+		"Summarize {{.Content":                 "parse prompt",
+		"Summarize {{.Body}}":                  "render prompt",
+		"{{range .Previous}}{{.Score}}{{end}}": "render prompt",
+		"{{summary}}":                          "parse prompt",
+	}
+	for text, want := range tests {
+		if err := prompt.Validate(text); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate(%q) = %v, want a %q error", text, err, want)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	def := prompt.Version("", prompt.DefaultSummarizer)
+	if def != prompt.Version(prompt.DefaultSummarizer, "") {
+		t.Error("blank text and the fallback itself have different versions")
+	}
+	if def == prompt.Version("Summarize: {{.Content}}", prompt.DefaultSummarizer) {
+		t.Error("a custom template has the default's version")
+	}
+}
@@ -2,7 +2,13 @@ package service
 
 import (
 	"context"
+	"log/slog"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"r3f-trends/internal/app/prompt"
+	"r3f-trends/internal/domain/entity"
 )
 
 type TopicSuggestion struct {
@@ -12,123 +18,447 @@ type TopicSuggestion struct {
 	Topics      []string `json:"topics"`
 }
 
+// SuggestRequest is a rendered suggester prompt and the IDs of the trends it
+// lists; every suggestion must cite at least one of them in Topics.
+type SuggestRequest struct {
+	Prompt   string
+	TrendIDs []string
+}
+
+// OutlineRequest is a rendered outliner prompt and the IDs of the trends it
+// cites; outline sections may only refer to those.
+type OutlineRequest struct {
+	Prompt   string
+	TrendIDs []string
+}
+
+type TrendTags struct {
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+}
+
 type LLMAgent interface {
 	Name() string
-	Summarize(ctx context.Context, content string) (string, error)
-	Suggest(ctx context.Context, trends []string, promptTemplate string) ([]TopicSuggestion, error)
+	Model() string
+	Summarize(ctx context.Context, prompt string) (string, error)
+	Suggest(ctx context.Context, req SuggestRequest) ([]TopicSuggestion, error)
+	Tag(ctx context.Context, prompt string) (*TrendTags, error)
+	Outline(ctx context.Context, req OutlineRequest) (*entity.Outline, error)
+	Draft(ctx context.Context, prompt string) (string, error)
+}
+
+type ProfileLoader interface {
+	Load(ctx context.Context, name string) (*entity.Profile, error)
+}
+
+type ContentFetcher interface {
+	Fetch(ctx context.Context, url string) (string, error)
+}
+
+type skipCacheKey struct{}
+
+// SkipCache marks ctx so LLM response caches call the model instead of
+// returning a stored answer. The fresh answer is still cached.
+func SkipCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+func CacheSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipCacheKey{}).(bool)
+	return skip
 }
 
+// DefaultChunkChars is the size above which article text is summarised in
+// chunks and the partial summaries are summarised again.
+const DefaultChunkChars = 6000
+
 type AgentService struct {
-	agent    LLMAgent
-	trendSvc *TrendService
+	agent          LLMAgent
+	trendSvc       *TrendService
+	profiles       ProfileLoader
+	defaultProfile string
+	content        ContentFetcher
+	chunkChars     int
+	summaryHistory int
+	usage          *UsageService
+	suggestions    *SuggestionService
+	drafts         DraftRepository
 }
 
-func NewAgentService(agent LLMAgent, trendSvc *TrendService) *AgentService {
-	return &AgentService{
-		agent:    agent,
-		trendSvc: trendSvc,
+type AgentOption func(*AgentService)
+
+// WithContentFetcher makes Summarize download the trend's article instead of
+// only sending its title.
+func WithContentFetcher(f ContentFetcher, chunkChars int) AgentOption {
+	return func(s *AgentService) {
+		s.content = f
+		if chunkChars > 0 {
+			s.chunkChars = chunkChars
+		}
 	}
 }
 
-func (s *AgentService) Summarize(ctx context.Context, trendID string) (string, error) {
+// WithSummaryHistory keeps up to n summaries per trend instead of replacing
+// the previous one.
+func WithSummaryHistory(n int) AgentOption {
+	return func(s *AgentService) {
+		s.summaryHistory = n
+	}
+}
+
+// WithUsage enforces the usage service's daily budget before LLM calls.
+func WithUsage(u *UsageService) AgentOption {
+	return func(s *AgentService) {
+		s.usage = u
+	}
+}
+
+// WithSuggestions stores generated suggestions, drops repeats and shows the
+// suggester the author's recent verdicts.
+func WithSuggestions(svc *SuggestionService) AgentOption {
+	return func(s *AgentService) {
+		s.suggestions = svc
+	}
+}
+
+func NewAgentService(agent LLMAgent, trendSvc *TrendService, profiles ProfileLoader, defaultProfile string, opts ...AgentOption) *AgentService {
+	s := &AgentService{
+		agent:          agent,
+		trendSvc:       trendSvc,
+		profiles:       profiles,
+		defaultProfile: defaultProfile,
+		chunkChars:     DefaultChunkChars,
+		summaryHistory: 1,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Summarize returns the stored summary for a trend, generating and saving
+// one first if there is none or refresh is set.
+func (s *AgentService) Summarize(ctx context.Context, trendID string, refresh bool) (*entity.SummaryRecord, error) {
 	trend, err := s.trendSvc.Get(ctx, trendID)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest := trend.LatestSummary(); latest != nil && !refresh {
+		return latest, nil
+	}
+
+	if refresh {
+		ctx = SkipCache(ctx)
+	}
+
+	rec, err := s.SummarizeTrend(ctx, trend)
+	if err != nil {
+		return nil, err
+	}
+
+	trend.AddSummary(*rec, s.summaryHistory)
+	if err := s.trendSvc.Update(ctx, trend); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// SummarizeTrend summarises an already loaded trend without storing the
+// result.
+func (s *AgentService) SummarizeTrend(ctx context.Context, trend *entity.Trend) (*entity.SummaryRecord, error) {
+	ctx, degraded, err := s.begin(ctx, s.defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profiles.Load(ctx, s.defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.summarize(ctx, s.trendContent(ctx, trend), []*entity.Trend{trend}, degraded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.SummaryRecord{
+		Text:          strings.TrimSpace(text),
+		Provider:      s.agent.Name(),
+		Model:         s.agent.Model(),
+		PromptVersion: prompt.Version(profile.Prompts().Summarizer, prompt.DefaultSummarizer),
+		CreatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+// Tag asks the model for a category and tags for a trend.
+func (s *AgentService) Tag(ctx context.Context, trend *entity.Trend) (*TrendTags, error) {
+	ctx, _, err := s.begin(ctx, s.defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profiles.Load(ctx, s.defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	data := prompt.NewData(profile, []*entity.Trend{trend})
+	data.Content = truncateChars(s.trendContent(ctx, trend), s.chunkChars)
+
+	rendered, err := prompt.Render(profile.Prompts().Tagger, prompt.DefaultTagger, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.agent.Tag(ctx, rendered)
+}
+
+// NameCluster asks the model for a short name for a storyline made of
+// trends with the given titles.
+func (s *AgentService) NameCluster(ctx context.Context, titles []string) (string, error) {
+	ctx, degraded, err := s.begin(ctx, s.defaultProfile)
+	if err != nil {
+		return "", err
+	}
+	if degraded {
+		return "", nil
+	}
+
+	profile, err := s.profiles.Load(ctx, s.defaultProfile)
+	if err != nil {
+		return "", err
+	}
+
+	data := prompt.NewData(profile, nil)
+	for _, title := range titles {
+		data.Trends = append(data.Trends, prompt.Trend{Title: title})
+	}
+
+	rendered, err := prompt.Render(profile.Prompts().Namer, prompt.DefaultNamer, data)
+	if err != nil {
+		return "", err
+	}
+
+	name, err := s.agent.Summarize(ctx, rendered)
 	if err != nil {
 		return "", err
 	}
 
+	// Keep the first line and drop quotes or a trailing full stop.
+	name, _, _ = strings.Cut(strings.TrimSpace(name), "\n")
+	return strings.Trim(strings.TrimSpace(name), "\"'.*"), nil
+}
+
+func (s *AgentService) SummarizeContent(ctx context.Context, content string) (string, error) {
+	ctx, degraded, err := s.begin(ctx, s.defaultProfile)
+	if err != nil {
+		return "", err
+	}
+
+	return s.summarize(ctx, content, nil, degraded)
+}
+
+// begin tags ctx with the profile for usage accounting and checks the daily
+// budget. degraded means the budget is spent but requests should still be
+// served, with as few LLM calls as possible.
+func (s *AgentService) begin(ctx context.Context, profile string) (context.Context, bool, error) {
+	ctx = ContextWithProfile(ctx, profile)
+	if s.usage == nil {
+		return ctx, false, nil
+	}
+
+	degraded, err := s.usage.CheckBudget(ctx)
+	return ctx, degraded, err
+}
+
+// trendContent returns the text to summarise for a trend: the fetched
+// article when available, otherwise the stored summary or the title.
+func (s *AgentService) trendContent(ctx context.Context, trend *entity.Trend) string {
 	content := trend.Title()
 	if trend.Summary() != "" {
 		content = trend.Summary()
 	}
 
-	return s.agent.Summarize(ctx, content)
+	if s.content == nil || trend.URL() == "" {
+		return content
+	}
+
+	text, err := s.content.Fetch(ctx, trend.URL())
+	if err != nil {
+		slog.WarnContext(ctx, "content fetch failed", "trend_id", trend.ID(), "url", trend.URL(), "error", err)
+		return content
+	}
+	if strings.TrimSpace(text) == "" {
+		return content
+	}
+
+	return trend.Title() + "\n\n" + text
 }
 
-func (s *AgentService) SummarizeContent(ctx context.Context, content string) (string, error) {
-	return s.agent.Summarize(ctx, content)
+func truncateChars(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+	return strings.ToValidUTF8(text[:max], "")
 }
 
-func (s *AgentService) SuggestTopics(ctx context.Context, profile string) ([]TopicSuggestion, error) {
-	trends, _, err := s.trendSvc.List(ctx, ListOptions{Limit: 50})
+func (s *AgentService) summarize(ctx context.Context, content string, trends []*entity.Trend, degraded bool) (string, error) {
+	profile, err := s.profiles.Load(ctx, s.defaultProfile)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	chunks := chunkText(content, s.chunkChars)
+	if len(chunks) == 1 || degraded {
+		return s.summarizeChunk(ctx, profile, chunks[0], trends)
 	}
 
-	var trendTitles []string
-	for _, t := range trends {
-		trendTitles = append(trendTitles, t.Title())
+	partials := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		partial, err := s.summarizeChunk(ctx, profile, chunk, trends)
+		if err != nil {
+			return "", err
+		}
+		partials = append(partials, strings.TrimSpace(partial))
 	}
 
-	prompt := s.buildPrompt(profile)
+	return s.summarizeChunk(ctx, profile, strings.Join(partials, "\n\n"), trends)
+}
+
+func (s *AgentService) summarizeChunk(ctx context.Context, profile *entity.Profile, content string, trends []*entity.Trend) (string, error) {
+	data := prompt.NewData(profile, trends)
+	data.Content = content
+
+	rendered, err := prompt.Render(profile.Prompts().Summarizer, prompt.DefaultSummarizer, data)
+	if err != nil {
+		return "", err
+	}
 
-	return s.agent.Suggest(ctx, trendTitles, prompt)
+	return s.agent.Summarize(ctx, rendered)
 }
 
-func (s *AgentService) buildPrompt(profile string) string {
-	switch profile {
-	case "tech":
-		return `Based on these trending tech topics, suggest 3 blog post ideas that would fit a blog about cloud engineering, Go, Rust, and AI.
+// SuggestResult holds the ideas returned by SuggestTopics and the trends
+// that were shown to the model to produce them.
+type SuggestResult struct {
+	Suggestions []*entity.Suggestion
+	Inputs      []SelectedTrend
+}
 
-Topics:
-{{topics}}
+// SuggestTopics asks for blog post ideas based on the trends the profile's
+// selection settings pick. With a suggestion store only ideas not proposed
+// before are returned.
+func (s *AgentService) SuggestTopics(ctx context.Context, profileName string) (*SuggestResult, error) {
+	ctx, _, err := s.begin(ctx, profileName)
+	if err != nil {
+		return nil, err
+	}
 
-For each suggestion, provide a title and brief description in JSON format:
-[
-  {"title": "...", "description": "...", "score": 0.95}
-]`
-	case "finance":
-		return `Based on these trending finance topics, suggest 3 blog post ideas about investing and market analysis.
+	profile, err := s.profiles.Load(ctx, profileName)
+	if err != nil {
+		return nil, err
+	}
 
-Topics:
-{{topics}}
+	all, _, err := s.trendSvc.List(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-For each suggestion, provide a title and brief description in JSON format:
-[
-  {"title": "...", "description": "...", "score": 0.95}
-]`
-	default:
-		return `Based on these trending topics, suggest 3 blog post ideas.
+	inputs := SelectTrends(all, profile, time.Now())
+	trends := make([]*entity.Trend, len(inputs))
+	for i, in := range inputs {
+		trends[i] = in.Trend
+	}
 
-Topics:
-{{topics}}
+	data := prompt.NewData(profile, trends)
+	if s.suggestions != nil {
+		recent, err := s.suggestions.Recent(ctx, profileName)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range recent {
+			data.Previous = append(data.Previous, prompt.Suggestion{Title: r.Title(), Status: string(r.Status())})
+		}
+	}
 
-For each suggestion, provide a title and brief description in JSON format:
-[
-  {"title": "...", "description": "...", "score": 0.95}
-]`
+	rendered, err := prompt.Render(profile.Prompts().Suggester, prompt.DefaultSuggester, data)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (s *AgentService) ExtractKeywords(ctx context.Context, content string) ([]string, error) {
-	summary, err := s.agent.Summarize(ctx, content)
+	ids := make([]string, len(trends))
+	for i, t := range trends {
+		ids[i] = t.ID()
+	}
+
+	// Always ask the model: a cached reply would only repeat ideas that are
+	// already stored and would be dropped as such.
+	topics, err := s.agent.Suggest(SkipCache(ctx), SuggestRequest{Prompt: rendered, TrendIDs: ids})
 	if err != nil {
 		return nil, err
 	}
 
-	words := strings.Fields(strings.ToLower(summary))
-	keywords := make([]string, 0)
-
-	stopWords := map[string]bool{
-		"the": true, "a": true, "an": true, "is": true, "are": true,
-		"was": true, "were": true, "be": true, "been": true, "being": true,
-		"have": true, "has": true, "had": true, "do": true, "does": true,
-		"did": true, "will": true, "would": true, "could": true, "should": true,
-		"may": true, "might": true, "must": true, "shall": true, "can": true,
-		"to": true, "of": true, "in": true, "for": true, "on": true,
-		"with": true, "at": true, "by": true, "from": true, "as": true,
-		"into": true, "through": true, "during": true, "before": true, "after": true,
-		"above": true, "below": true, "between": true, "under": true, "again": true,
-		"further": true, "then": true, "once": true, "this": true, "that": true,
-		"these": true, "those": true, "and": true, "but": true, "or": true,
-		"if": true, "because": true, "while": true, "although": true, "though": true,
-	}
-
-	for _, word := range words {
-		word = strings.Trim(word, ".,!?;:\"'()[]{}")
-		if len(word) > 2 && !stopWords[word] {
-			keywords = append(keywords, word)
+	suggestions := make([]*entity.Suggestion, len(topics))
+	for i, t := range topics {
+		sug := entity.NewSuggestion(profileName, t.Title)
+		sug.SetDescription(t.Description)
+		sug.SetScore(t.Score)
+		sug.SetTopics(t.Topics)
+		suggestions[i] = sug
+	}
+
+	if s.suggestions != nil {
+		suggestions, err = s.suggestions.Record(ctx, profileName, suggestions)
+		if err != nil {
+			return nil, err
 		}
 	}
+	return &SuggestResult{Suggestions: suggestions, Inputs: inputs}, nil
+}
+
+// chunkText splits text into pieces of at most size bytes, breaking at
+// paragraph boundaries where possible.
+func chunkText(text string, size int) []string {
+	if size <= 0 || len(text) <= size {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, para := range strings.Split(text, "\n\n") {
+		for len(para) > size {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			cut := strings.LastIndexByte(para[:size], ' ')
+			if cut <= 0 {
+				// No space to break at, as in CJK text: cut between runes.
+				cut = size
+				for cut > 0 && !utf8.RuneStart(para[cut]) {
+					cut--
+				}
+				if cut == 0 {
+					_, cut = utf8.DecodeRuneInString(para)
+				}
+			}
+			chunks = append(chunks, para[:cut])
+			para = strings.TrimSpace(para[cut:])
+		}
+
+		if current.Len() > 0 && current.Len()+2+len(para) > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(para)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
 
-	return keywords, nil
+	return chunks
 }
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+type stubFetcher map[string]string
+
+func (f stubFetcher) Fetch(ctx context.Context, url string) (string, error) {
+	return f[url], nil
+}
+
+func newTrendService(t *testing.T, trends ...*entity.Trend) *service.TrendService {
+	t.Helper()
+	repo := markdown.NewTrendRepositoryAdapter(t.TempDir())
+	if err := repo.SaveBatch(context.Background(), trends); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	return service.NewTrendService(repo)
+}
+
+func TestSummarizeChunksLongArticles(t *testing.T) {
+	trend := entity.NewTrend("hn-1", "Go 1.26 is released", "https://go.dev/blog/go1.26")
+	paragraph := strings.Repeat("The new garbage collector is enabled by default. ", 10)
+	article := strings.Join([]string{paragraph, paragraph, paragraph}, "\n\n")
+
+	provider := fake.New()
+	svc := service.NewAgentService(
+		llm.NewAgent(provider),
+		newTrendService(t, trend),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+		service.WithContentFetcher(stubFetcher{trend.URL(): article}, 600),
+	)
+
+	if _, err := svc.Summarize(context.Background(), "hn-1", false); err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+
+	calls := provider.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("got %d LLM calls, want 3 chunks + 1 combine", len(calls))
+	}
+	if !strings.Contains(calls[0].Messages[0].Content, "garbage collector") {
+		t.Errorf("first chunk does not contain article text: %q", calls[0].Messages[0].Content)
+	}
+	if !strings.Contains(calls[3].Messages[0].Content, "Summary [") {
+		t.Errorf("final call does not combine partial summaries: %q", calls[3].Messages[0].Content)
+	}
+}
+
+func TestSummarizeChunksTextWithoutSpaces(t *testing.T) {
+	trend := entity.NewTrend("hn-3", "新しいガベージコレクタ", "https://example.jp/gc")
+	article := strings.Repeat("新しいガベージコレクタがデフォルトで有効になりました。", 40)
+
+	provider := fake.New()
+	svc := service.NewAgentService(
+		llm.NewAgent(provider),
+		newTrendService(t, trend),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+		service.WithContentFetcher(stubFetcher{trend.URL(): article}, 500),
+	)
+
+	if _, err := svc.Summarize(context.Background(), "hn-3", false); err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+
+	calls := provider.Calls()
+	if len(calls) < 3 {
+		t.Fatalf("got %d LLM calls, want the article split into chunks", len(calls))
+	}
+	for i, call := range calls[:len(calls)-1] {
+		if content := call.Messages[0].Content; !utf8.ValidString(content) {
+			t.Errorf("call %d sent invalid UTF-8: %q", i, content)
+		}
+	}
+}
+
+func TestSummarizeFallsBackToTitle(t *testing.T) {
+	trend := entity.NewTrend("hn-2", "Ask HN: Is anyone moving off Kubernetes?", "")
+
+	provider := fake.New()
+	svc := service.NewAgentService(
+		llm.NewAgent(provider),
+		newTrendService(t, trend),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+		service.WithContentFetcher(stubFetcher{}, 0),
+	)
+
+	if _, err := svc.Summarize(context.Background(), "hn-2", false); err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+
+	calls := provider.Calls()
+	if len(calls) != 1 || !strings.Contains(calls[0].Messages[0].Content, trend.Title()) {
+		t.Errorf("calls = %#v", calls)
+	}
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// DefaultClusterThreshold is the embedding similarity above which two
+	// trends are taken to cover the same story.
+	DefaultClusterThreshold = 0.5
+	// DefaultOverlapThreshold is the title word overlap (Jaccard) used
+	// instead when there are no embeddings.
+	DefaultOverlapThreshold = 0.3
+	// DefaultClusterLookback is how many earlier days are searched for a
+	// storyline that a new cluster continues.
+	DefaultClusterLookback = 3
+	// clusterKeywords is how many keywords are kept per cluster.
+	clusterKeywords = 3
+)
+
+type ClusterRepository interface {
+	SaveClusters(ctx context.Context, date string, clusters []*entity.Cluster) error
+	// ListClusters returns nil if the date has not been clustered.
+	ListClusters(ctx context.Context, date string) ([]*entity.Cluster, error)
+}
+
+type ClusterConfig struct {
+	// Threshold overrides the similarity needed to join a cluster.
+	Threshold    float64
+	LookbackDays int
+}
+
+// ClusterService groups each day's trends into storylines and links them to
+// the storylines of the previous days.
+type ClusterService struct {
+	trendSvc   *TrendService
+	repo       ClusterRepository
+	cfg        ClusterConfig
+	embeddings *EmbeddingService
+	namer      *AgentService
+	now        func() time.Time
+}
+
+type ClusterOption func(*ClusterService)
+
+// WithClusterEmbeddings compares trends by embedding similarity instead of
+// title word overlap.
+func WithClusterEmbeddings(e *EmbeddingService) ClusterOption {
+	return func(s *ClusterService) {
+		s.embeddings = e
+	}
+}
+
+// WithClusterNamer asks the LLM to name clusters with more than one trend.
+// Clusters fall back to keyword names if that fails.
+func WithClusterNamer(agentSvc *AgentService) ClusterOption {
+	return func(s *ClusterService) {
+		s.namer = agentSvc
+	}
+}
+
+func NewClusterService(trendSvc *TrendService, repo ClusterRepository, cfg ClusterConfig, opts ...ClusterOption) *ClusterService {
+	if cfg.LookbackDays <= 0 {
+		cfg.LookbackDays = DefaultClusterLookback
+	}
+	s := &ClusterService{
+		trendSvc: trendSvc,
+		repo:     repo,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *ClusterService) Name() string {
+	return "clustering"
+}
+
+func (s *ClusterService) Process(ctx context.Context, profile string, result *entity.CollectionResult) error {
+	_, err := s.Cluster(ctx, s.now().Format("2006-01-02"))
+	return err
+}
+
+// Clusters returns the stored clusters of date, clustering the day first if
+// it has not been done yet or refresh is set.
+func (s *ClusterService) Clusters(ctx context.Context, date string, refresh bool) ([]*entity.Cluster, error) {
+	if !refresh {
+		clusters, err := s.repo.ListClusters(ctx, date)
+		if err != nil || clusters != nil {
+			return clusters, err
+		}
+	}
+	return s.Cluster(ctx, date)
+}
+
+// Stored returns the stored clusters of date without clustering it, or nil
+// if the date has not been clustered.
+func (s *ClusterService) Stored(ctx context.Context, date string) ([]*entity.Cluster, error) {
+	return s.repo.ListClusters(ctx, date)
+}
+
+// Cluster groups the trends collected on date, largest cluster first, and
+// stores the result.
+func (s *ClusterService) Cluster(ctx context.Context, date string) ([]*entity.Cluster, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, err
+	}
+
+	trends, _, err := s.trendSvc.GetByDate(ctx, date, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Earlier days are needed to continue their storylines.
+	type previous struct {
+		cluster *entity.Cluster
+		trends  []*entity.Trend
+	}
+	var earlier []previous
+	byID := make(map[string]*entity.Trend)
+	all := append([]*entity.Trend(nil), trends...)
+	for i := 1; i <= s.cfg.LookbackDays; i++ {
+		d := day.AddDate(0, 0, -i).Format("2006-01-02")
+		clusters, err := s.repo.ListClusters(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if len(clusters) == 0 {
+			continue
+		}
+
+		dayTrends, _, err := s.trendSvc.GetByDate(ctx, d, ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range dayTrends {
+			byID[t.ID()] = t
+		}
+		all = append(all, dayTrends...)
+
+		for _, c := range clusters {
+			p := previous{cluster: c}
+			for _, id := range c.TrendIDs {
+				if t, ok := byID[id]; ok {
+					p.trends = append(p.trends, t)
+				}
+			}
+			earlier = append(earlier, p)
+		}
+	}
+
+	similar, err := s.similarity(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]*entity.Trend(nil), trends...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score() > sorted[j].Score() })
+
+	// Single-link clustering: a trend joins the cluster holding its most
+	// similar trend, if that is similar enough.
+	var groups [][]*entity.Trend
+	for _, t := range sorted {
+		best, bestSim := -1, 0.0
+		for i, g := range groups {
+			if sim := maxSimilarity(similar, t, g); sim > bestSim {
+				best, bestSim = i, sim
+			}
+		}
+		if best >= 0 && bestSim >= s.threshold() {
+			groups[best] = append(groups[best], t)
+			continue
+		}
+		groups = append(groups, []*entity.Trend{t})
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+
+	clusters := make([]*entity.Cluster, 0, len(groups))
+	continued := make(map[string]bool)
+	for _, g := range groups {
+		c := &entity.Cluster{
+			ID:        clusterID(date, g[0].ID()),
+			Date:      date,
+			Keywords:  topTerms(g, clusterKeywords),
+			FirstSeen: date,
+		}
+		for _, t := range g {
+			c.TrendIDs = append(c.TrendIDs, t.ID())
+		}
+
+		// Continue the most similar earlier storyline, most recent first on
+		// ties, unless another cluster of the day already did.
+		bestSim := 0.0
+		for _, p := range earlier {
+			if continued[p.cluster.ID] {
+				continue
+			}
+			sim := 0.0
+			for _, t := range g {
+				sim = max(sim, maxSimilarity(similar, t, p.trends))
+			}
+			if sim >= s.threshold() && sim > bestSim {
+				bestSim = sim
+				c.ID = p.cluster.ID
+				c.FirstSeen = p.cluster.FirstSeen
+			}
+		}
+		continued[c.ID] = true
+
+		s.name(ctx, c, g)
+		clusters = append(clusters, c)
+	}
+
+	if err := s.repo.SaveClusters(ctx, date, clusters); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+func (s *ClusterService) threshold() float64 {
+	if s.cfg.Threshold > 0 {
+		return s.cfg.Threshold
+	}
+	if s.embeddings != nil {
+		return DefaultClusterThreshold
+	}
+	return DefaultOverlapThreshold
+}
+
+// similarity returns a function comparing two of trends, by embeddings if
+// available and by title word overlap otherwise.
+func (s *ClusterService) similarity(ctx context.Context, trends []*entity.Trend) (func(a, b *entity.Trend) float64, error) {
+	if s.embeddings != nil {
+		vectors, err := s.embeddings.Vectors(ctx, trends)
+		if err != nil {
+			return nil, err
+		}
+		return func(a, b *entity.Trend) float64 {
+			return Cosine(vectors[a.ID()], vectors[b.ID()])
+		}, nil
+	}
+
+	words := make(map[string]map[string]bool, len(trends))
+	for _, t := range trends {
+		set := make(map[string]bool)
+		for _, w := range terms(t.Title()) {
+			set[w] = true
+		}
+		words[t.ID()] = set
+	}
+	return func(a, b *entity.Trend) float64 {
+		return jaccard(words[a.ID()], words[b.ID()])
+	}, nil
+}
+
+// name names c by the LLM when configured and the cluster has more than
+// one trend, by its keywords otherwise, or by its only trend's title.
+func (s *ClusterService) name(ctx context.Context, c *entity.Cluster, trends []*entity.Trend) {
+	if len(trends) == 1 {
+		c.Name, c.NamedBy = trends[0].Title(), entity.ClusterNamedByTitle
+		return
+	}
+
+	if s.namer != nil {
+		titles := make([]string, len(trends))
+		for i, t := range trends {
+			titles[i] = t.Title()
+		}
+		name, err := s.namer.NameCluster(ctx, titles)
+		if err == nil && name != "" {
+			c.Name, c.NamedBy = name, entity.ClusterNamedByLLM
+			return
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "cluster naming failed, using keywords", "error", err)
+		}
+	}
+
+	if len(c.Keywords) == 0 {
+		c.Name, c.NamedBy = trends[0].Title(), entity.ClusterNamedByTitle
+		return
+	}
+	c.Name, c.NamedBy = strings.Join(c.Keywords, ", "), entity.ClusterNamedByKeywords
+}
+
+func maxSimilarity(similar func(a, b *entity.Trend) float64, t *entity.Trend, group []*entity.Trend) float64 {
+	best := 0.0
+	for _, other := range group {
+		best = max(best, similar(t, other))
+	}
+	return best
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// topTerms returns the n words that occur in the most titles of trends,
+// ignoring words found in only one title of a larger group.
+func topTerms(trends []*entity.Trend, n int) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, t := range trends {
+		seen := make(map[string]bool)
+		for _, w := range terms(t.Title()) {
+			if seen[w] {
+				continue
+			}
+			seen[w] = true
+			if counts[w] == 0 {
+				order = append(order, w)
+			}
+			counts[w]++
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+
+	var top []string
+	for _, w := range order {
+		if len(top) == n || (len(trends) > 1 && counts[w] < 2) {
+			break
+		}
+		top = append(top, w)
+	}
+	return top
+}
+
+func clusterID(date, trendID string) string {
+	sum := sha1.Sum([]byte(date + "/" + trendID))
+	return hex.EncodeToString(sum[:6])
+}
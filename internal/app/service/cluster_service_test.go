@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestClusterStorylines(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := markdown.NewTrendRepositoryAdapter(dir)
+	trendSvc := service.NewTrendService(repo)
+	clusterSvc := service.NewClusterService(trendSvc, markdown.NewClusterRepository(dir), service.ClusterConfig{})
+
+	today := time.Now().Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	// Yesterday's file is written as today's and then renamed.
+	if err := repo.SaveBatch(ctx, []*entity.Trend{
+		entity.NewTrend("hn-1", "Kubernetes 1.34 released with sidecar containers", ""),
+		entity.NewTrend("hn-2", "Sidecar containers are GA in Kubernetes 1.34", ""),
+		entity.NewTrend("hn-3", "A history of the pocket calculator", ""),
+	}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	trendsDir := filepath.Join(dir, "tech", "trends")
+	if err := os.Rename(filepath.Join(trendsDir, today+".md"), filepath.Join(trendsDir, yesterday+".md")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	first, err := clusterSvc.Cluster(ctx, yesterday)
+	if err != nil {
+		t.Fatalf("Cluster(yesterday): %v", err)
+	}
+	if len(first) != 2 || len(first[0].TrendIDs) != 2 {
+		t.Fatalf("clusters = %+v, want the two Kubernetes stories together", first)
+	}
+	if first[0].NamedBy != entity.ClusterNamedByKeywords || first[1].Name != "A history of the pocket calculator" {
+		t.Errorf("names = %q (%s), %q", first[0].Name, first[0].NamedBy, first[1].Name)
+	}
+
+	if err := repo.SaveBatch(ctx, []*entity.Trend{
+		entity.NewTrend("hn-4", "Upgrading to Kubernetes 1.34: sidecar containers in practice", ""),
+	}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+
+	second, err := clusterSvc.Clusters(ctx, today, false)
+	if err != nil {
+		t.Fatalf("Clusters(today): %v", err)
+	}
+	if len(second) != 1 || second[0].ID != first[0].ID || second[0].FirstSeen != yesterday {
+		t.Errorf("today's cluster %+v does not continue storyline %s", second[0], first[0].ID)
+	}
+}
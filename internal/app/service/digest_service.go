@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/app/digest"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// DefaultDigestLimit is how many trends a digest lists when neither the
+	// request nor digest.limit says.
+	DefaultDigestLimit = 20
+	// MaxDigestDays is the longest period a digest can cover.
+	MaxDigestDays = 31
+)
+
+type DigestRepository interface {
+	// SaveDigest stores the digest, replacing one for the same period, and
+	// sets its paths.
+	SaveDigest(ctx context.Context, d *entity.Digest) error
+}
+
+// Mailer sends an email with plain text and HTML alternatives.
+type Mailer interface {
+	Send(ctx context.Context, to []string, subject, text, html string) error
+}
+
+// DigestTemplates are user-supplied templates; empty ones fall back to the
+// defaults in package digest.
+type DigestTemplates struct {
+	Subject  string
+	Markdown string
+	HTML     string
+}
+
+type DigestConfig struct {
+	Limit     int
+	Templates DigestTemplates
+	// To are the recipients when a digest is sent.
+	To []string
+}
+
+type DigestRequest struct {
+	Profile string `json:"profile"`
+	// From and To are YYYY-MM-DD and default to today.
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Limit int    `json:"limit"`
+	// Send emails the digest when a mailer is configured.
+	Send bool `json:"send"`
+}
+
+// DigestService turns the top trends of a period into a digest for the
+// team newsletter.
+type DigestService struct {
+	trendSvc *TrendService
+	profiles ProfileLoader
+	repo     DigestRepository
+	cfg      DigestConfig
+	clusters *ClusterService
+	mailer   Mailer
+	now      func() time.Time
+}
+
+type DigestOption func(*DigestService)
+
+// WithDigestClusters lists one trend per storyline, noting how many others
+// it stands for. Days that have not been clustered are listed ungrouped.
+func WithDigestClusters(c *ClusterService) DigestOption {
+	return func(s *DigestService) {
+		s.clusters = c
+	}
+}
+
+// WithMailer lets digests be emailed to DigestConfig.To.
+func WithMailer(m Mailer) DigestOption {
+	return func(s *DigestService) {
+		s.mailer = m
+	}
+}
+
+func NewDigestService(trendSvc *TrendService, profiles ProfileLoader, repo DigestRepository, cfg DigestConfig, opts ...DigestOption) *DigestService {
+	if cfg.Limit <= 0 {
+		cfg.Limit = DefaultDigestLimit
+	}
+	s := &DigestService{
+		trendSvc: trendSvc,
+		profiles: profiles,
+		repo:     repo,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Generate renders and stores the digest of req's period and sends it if
+// asked to. A digest with no trends is stored but not sent.
+func (s *DigestService) Generate(ctx context.Context, req DigestRequest) (*entity.Digest, error) {
+	profile, err := s.profiles.Load(ctx, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, days, err := s.period(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var trends []*entity.Trend
+	storyline := make(map[string]*entity.Cluster)
+	sources := profileSources(profile)
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i).Format("2006-01-02")
+		dayTrends, _, err := s.trendSvc.GetByDate(ctx, date, ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range dayTrends {
+			if len(sources) == 0 || t.SourceID() == "" || sources[t.SourceID()] {
+				trends = append(trends, t)
+			}
+		}
+
+		// Only days that were clustered after collection are grouped;
+		// clustering them here could mean a month of LLM naming calls.
+		if s.clusters != nil && len(dayTrends) > 0 {
+			clusters, err := s.clusters.Stored(ctx, date)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range clusters {
+				for _, id := range c.TrendIDs {
+					storyline[id] = c
+				}
+			}
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = s.cfg.Limit
+	}
+
+	data := digest.Data{
+		Profile: digest.Profile{
+			Name:        profile.Name(),
+			DisplayName: profile.DisplayName(),
+			Description: profile.Description(),
+		},
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Total:  len(trends),
+		Trends: topDigestTrends(trends, storyline, limit),
+	}
+	data.Period = digest.Period(data.From, data.To)
+
+	d := &entity.Digest{
+		ID:        data.From,
+		Profile:   profile.Name(),
+		From:      data.From,
+		To:        data.To,
+		CreatedAt: s.now().UTC(),
+	}
+	if data.From != data.To {
+		d.ID = data.From + "_" + data.To
+	}
+	for _, t := range data.Trends {
+		d.TrendIDs = append(d.TrendIDs, t.ID)
+	}
+
+	if d.Subject, err = digest.RenderText(s.cfg.Templates.Subject, digest.DefaultSubject, data); err != nil {
+		return nil, err
+	}
+	d.Subject = strings.TrimSpace(d.Subject)
+	if d.Markdown, err = digest.RenderText(s.cfg.Templates.Markdown, digest.DefaultMarkdown, data); err != nil {
+		return nil, err
+	}
+	if d.HTML, err = digest.RenderHTML(s.cfg.Templates.HTML, digest.DefaultHTML, data); err != nil {
+		return nil, err
+	}
+
+	if req.Send {
+		switch {
+		case s.mailer == nil || len(s.cfg.To) == 0:
+			return nil, fmt.Errorf("%w: digest email is not configured", domain.ErrInvalidRequest)
+		case len(d.TrendIDs) == 0:
+			slog.InfoContext(ctx, "digest has no trends, not sending", "profile", d.Profile, "digest", d.ID)
+		default:
+			if err := s.mailer.Send(ctx, s.cfg.To, d.Subject, d.Markdown, d.HTML); err != nil {
+				return nil, fmt.Errorf("send digest: %w", err)
+			}
+			d.SentTo = s.cfg.To
+		}
+	}
+
+	if err := s.repo.SaveDigest(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *DigestService) period(from, to string) (time.Time, time.Time, int, error) {
+	today := s.now().Format("2006-01-02")
+	if to == "" {
+		to = today
+	}
+	if from == "" {
+		from = to
+	}
+
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("%w: from: %v", domain.ErrInvalidRequest, err)
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("%w: to: %v", domain.ErrInvalidRequest, err)
+	}
+
+	days := int(end.Sub(start).Hours()/24) + 1
+	if days < 1 || days > MaxDigestDays {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("%w: digest period must be 1 to %d days", domain.ErrInvalidRequest, MaxDigestDays)
+	}
+	return start, end, days, nil
+}
+
+// topDigestTrends ranks starred trends first, then by score, and keeps the
+// best trend of each storyline, counting the others as related.
+func topDigestTrends(trends []*entity.Trend, storyline map[string]*entity.Cluster, limit int) []digest.Trend {
+	sorted := append([]*entity.Trend(nil), trends...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Starred() != sorted[j].Starred() {
+			return sorted[i].Starred()
+		}
+		return sorted[i].Score() > sorted[j].Score()
+	})
+
+	var top []digest.Trend
+	byStoryline := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, t := range sorted {
+		// A trend collected on several days is listed once.
+		if seen[t.ID()] {
+			continue
+		}
+		seen[t.ID()] = true
+
+		cluster := storyline[t.ID()]
+		if cluster != nil {
+			if i, ok := byStoryline[cluster.ID]; ok {
+				top[i].Related++
+				continue
+			}
+		}
+		if len(top) == limit {
+			continue
+		}
+
+		summary := t.Summary()
+		if latest := t.LatestSummary(); latest != nil {
+			summary = latest.Text
+		}
+		item := digest.Trend{
+			ID:      t.ID(),
+			Title:   t.Title(),
+			URL:     t.URL(),
+			Source:  t.Source(),
+			Score:   t.Score(),
+			Starred: t.Starred(),
+			Summary: strings.TrimSpace(summary),
+			Tags:    t.Tags(),
+		}
+		if cluster != nil {
+			if cluster.Name != t.Title() {
+				item.Storyline = cluster.Name
+			}
+			byStoryline[cluster.ID] = len(top)
+		}
+		top = append(top, item)
+	}
+	return top
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+type stubMailer struct {
+	to            []string
+	subject, html string
+}
+
+func (m *stubMailer) Send(ctx context.Context, to []string, subject, text, html string) error {
+	m.to, m.subject, m.html = to, subject, html
+	return nil
+}
+
+func TestDigestOnePerStoryline(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	first := entity.NewTrend("hn-1", "Kubernetes 1.34 released with sidecar containers", "https://example.com/k8s")
+	first.SetScore(300)
+	second := entity.NewTrend("hn-2", "Sidecar containers are GA in Kubernetes 1.34", "")
+	second.SetScore(200)
+	starred := entity.NewTrend("hn-3", "A history of the <pocket> calculator", "https://example.com/calc")
+	starred.SetStarred(true)
+	starred.SetSummary("Calculators got small.")
+
+	repo := markdown.NewTrendRepositoryAdapter(dir)
+	if err := repo.SaveBatch(ctx, []*entity.Trend{first, second, starred}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	trendSvc := service.NewTrendService(repo)
+
+	mailer := &stubMailer{}
+	clusterRepo := markdown.NewClusterRepository(dir)
+	clusterSvc := service.NewClusterService(trendSvc, clusterRepo, service.ClusterConfig{})
+	svc := service.NewDigestService(trendSvc, yaml.NewProfileLoader("../../../config/profiles"), markdown.NewDigestRepository(dir),
+		service.DigestConfig{To: []string{"team@example.com"}},
+		service.WithDigestClusters(clusterSvc),
+		service.WithMailer(mailer),
+	)
+
+	// A day that was not clustered is listed as it is, and left unclustered.
+	today := time.Now().Format("2006-01-02")
+	d, err := svc.Generate(ctx, service.DigestRequest{Profile: "tech"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if strings.Join(d.TrendIDs, ",") != "hn-3,hn-1,hn-2" {
+		t.Errorf("trends = %v, want every trend of the unclustered day", d.TrendIDs)
+	}
+	if stored, _ := clusterRepo.ListClusters(ctx, today); stored != nil {
+		t.Errorf("generating the digest clustered %s: %+v", today, stored)
+	}
+
+	if _, err := clusterSvc.Cluster(ctx, today); err != nil {
+		t.Fatalf("Cluster: %v", err)
+	}
+	d, err = svc.Generate(ctx, service.DigestRequest{Profile: "tech", Send: true})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if strings.Join(d.TrendIDs, ",") != "hn-3,hn-1" {
+		t.Errorf("trends = %v, want the starred trend, then one per storyline", d.TrendIDs)
+	}
+	if !strings.Contains(d.Markdown, "Calculators got small.") || !strings.Contains(d.Markdown, "1 related") {
+		t.Errorf("markdown:\n%s", d.Markdown)
+	}
+	if !strings.Contains(d.HTML, "A history of the &lt;pocket&gt; calculator") {
+		t.Errorf("html does not escape titles:\n%s", d.HTML)
+	}
+	if mailer.subject != d.Subject || len(d.SentTo) != 1 {
+		t.Errorf("sent %q to %v", mailer.subject, d.SentTo)
+	}
+
+	saved, err := os.ReadFile(d.Path)
+	if err != nil || !strings.HasSuffix(string(saved), d.Markdown) {
+		t.Errorf("saved digest: %v\n%s", err, saved)
+	}
+	if _, err := os.Stat(d.HTMLPath); err != nil {
+		t.Errorf("html not saved: %v", err)
+	}
+
+	_, err = svc.Generate(ctx, service.DigestRequest{Profile: "tech", From: "2026-01-01", To: "2026-03-01"})
+	if !errors.Is(err, domain.ErrInvalidRequest) {
+		t.Errorf("err = %v, want ErrInvalidRequest for a long period", err)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/app/prompt"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// MaxDraftSources caps how many trends a single draft is written from.
+	MaxDraftSources = 8
+	// DraftSourceChars is how much text of each source goes into the
+	// outliner and drafter prompts.
+	DraftSourceChars = 3000
+)
+
+type DraftRepository interface {
+	SaveDraft(ctx context.Context, draft *entity.Draft) error
+}
+
+// DraftRequest selects what a post is written from: a stored suggestion and
+// the trends it cites, explicit trend IDs, or, when both are empty, the
+// starred trends.
+type DraftRequest struct {
+	Profile      string
+	SuggestionID string
+	TrendIDs     []string
+}
+
+// WithDrafts stores generated drafts. Without it Draft still returns them.
+func WithDrafts(repo DraftRepository) AgentOption {
+	return func(s *AgentService) {
+		s.drafts = repo
+	}
+}
+
+// Draft outlines and writes a blog post from trends, citing their URLs. A
+// draft written from a suggestion marks the suggestion as written.
+func (s *AgentService) Draft(ctx context.Context, req DraftRequest) (*entity.Draft, error) {
+	if req.Profile == "" {
+		req.Profile = s.defaultProfile
+	}
+
+	ctx, degraded, err := s.begin(ctx, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profiles.Load(ctx, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var idea *entity.Suggestion
+	if req.SuggestionID != "" {
+		if s.suggestions == nil {
+			return nil, fmt.Errorf("suggestion %s: %w", req.SuggestionID, domain.ErrNotFound)
+		}
+		idea, err = s.suggestions.Get(ctx, req.Profile, req.SuggestionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trends, err := s.draftSources(ctx, idea, req.TrendIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	data := prompt.NewData(profile, trends)
+	if idea != nil {
+		data.Idea = prompt.Suggestion{Title: idea.Title(), Description: idea.Description(), Status: string(idea.Status())}
+	}
+	ids := make([]string, len(trends))
+	for i, t := range trends {
+		ids[i] = t.ID()
+		data.Trends[i].Content = s.sourceContent(ctx, t, degraded)
+	}
+
+	rendered, err := prompt.Render(profile.Prompts().Outliner, prompt.DefaultOutliner, data)
+	if err != nil {
+		return nil, err
+	}
+	outline, err := s.agent.Outline(ctx, OutlineRequest{Prompt: rendered, TrendIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]entity.DraftSource, len(trends))
+	refs := make(map[string]int, len(trends))
+	for i, t := range trends {
+		sources[i] = entity.DraftSource{Ref: i + 1, TrendID: t.ID(), Title: t.Title(), URL: t.URL()}
+		refs[t.ID()] = i + 1
+	}
+
+	data.Outline = outlineMarkdown(outline, refs)
+	rendered, err = prompt.Render(profile.Prompts().Drafter, prompt.DefaultDrafter, data)
+	if err != nil {
+		return nil, err
+	}
+	text, err := s.agent.Draft(ctx, rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &entity.Draft{
+		Profile:   req.Profile,
+		Title:     outline.Title,
+		Outline:   *outline,
+		Markdown:  strings.TrimSpace(text) + "\n\n" + sourcesMarkdown(sources),
+		Sources:   sources,
+		Provider:  s.agent.Name(),
+		Model:     s.agent.Model(),
+		CreatedAt: time.Now().UTC(),
+	}
+	draft.ID = draft.CreatedAt.Format("2006-01-02") + "-" + entity.DraftSlug(draft.Title)
+	if idea != nil {
+		draft.SuggestionID = idea.ID()
+	}
+
+	if s.drafts != nil {
+		if err := s.drafts.SaveDraft(ctx, draft); err != nil {
+			return nil, err
+		}
+	}
+	if idea != nil {
+		if _, err := s.suggestions.SetStatus(ctx, req.Profile, idea.ID(), entity.SuggestionWritten); err != nil {
+			return nil, err
+		}
+	}
+
+	return draft, nil
+}
+
+// draftSources loads the trends a draft cites: the suggestion's topics and
+// the requested IDs, or the most recent starred trends if there are none.
+func (s *AgentService) draftSources(ctx context.Context, idea *entity.Suggestion, trendIDs []string) ([]*entity.Trend, error) {
+	var ids []string
+	if idea != nil {
+		ids = append(ids, idea.Topics()...)
+	}
+	ids = append(ids, trendIDs...)
+
+	var trends []*entity.Trend
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		trend, err := s.trendSvc.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		trends = append(trends, trend)
+	}
+
+	if len(ids) == 0 {
+		all, _, err := s.trendSvc.List(ctx, ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range all {
+			if t.Starred() {
+				trends = append(trends, t)
+			}
+		}
+	}
+
+	if len(trends) == 0 {
+		return nil, domain.ErrNoDraftSources
+	}
+	if len(trends) > MaxDraftSources {
+		trends = trends[:MaxDraftSources]
+	}
+	return trends, nil
+}
+
+// sourceContent is the stored summary of a trend followed by its article
+// text. Articles are not fetched once the budget is spent.
+func (s *AgentService) sourceContent(ctx context.Context, trend *entity.Trend, degraded bool) string {
+	var parts []string
+	if latest := trend.LatestSummary(); latest != nil {
+		parts = append(parts, latest.Text)
+	} else if trend.Summary() != "" {
+		parts = append(parts, trend.Summary())
+	}
+
+	if s.content != nil && trend.URL() != "" && !degraded {
+		text, err := s.content.Fetch(ctx, trend.URL())
+		if err != nil {
+			slog.WarnContext(ctx, "content fetch failed", "trend_id", trend.ID(), "url", trend.URL(), "error", err)
+		} else if strings.TrimSpace(text) != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return truncateChars(strings.Join(parts, "\n\n"), DraftSourceChars)
+}
+
+// outlineMarkdown renders an outline for the drafter prompt, with sources
+// given as citation numbers.
+func outlineMarkdown(outline *entity.Outline, refs map[string]int) string {
+	var b strings.Builder
+	b.WriteString("# " + outline.Title + "\n")
+	for _, section := range outline.Sections {
+		b.WriteString("\n## " + section.Heading + "\n")
+		for _, point := range section.Points {
+			b.WriteString("- " + point + "\n")
+		}
+
+		var cites []string
+		for _, id := range section.Sources {
+			if ref, ok := refs[id]; ok {
+				cites = append(cites, fmt.Sprintf("[%d]", ref))
+			}
+		}
+		if len(cites) > 0 {
+			b.WriteString("Sources: " + strings.Join(cites, ", ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+func sourcesMarkdown(sources []entity.DraftSource) string {
+	var b strings.Builder
+	b.WriteString("## Sources\n\n")
+	for _, src := range sources {
+		url := src.URL
+		if url == "" {
+			fmt.Fprintf(&b, "- [%d] %s\n", src.Ref, src.Title)
+			continue
+		}
+		fmt.Fprintf(&b, "- [%d] [%s](%s)\n", src.Ref, src.Title, url)
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestDraftFromStarredTrends(t *testing.T) {
+	ctx := context.Background()
+
+	starred := entity.NewTrend("hn-1", "Go 1.30 ships arenas", "https://example.com/arenas")
+	starred.SetStarred(true)
+	other := entity.NewTrend("hn-2", "Unrelated news", "https://example.com/other")
+
+	provider := fake.New()
+	svc := service.NewAgentService(
+		llm.NewAgent(provider),
+		newTrendService(t, starred, other),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+		service.WithContentFetcher(stubFetcher{"https://example.com/arenas": "Arenas let you free memory in bulk."}, 0),
+		service.WithDrafts(markdown.NewDraftRepository(t.TempDir())),
+	)
+
+	draft, err := svc.Draft(ctx, service.DraftRequest{})
+	if err != nil {
+		t.Fatalf("Draft: %v", err)
+	}
+
+	if len(draft.Sources) != 1 || draft.Sources[0].TrendID != "hn-1" {
+		t.Fatalf("sources = %+v, want only the starred trend", draft.Sources)
+	}
+	if len(draft.Outline.Sections) == 0 || draft.Outline.Sections[0].Sources[0] != "hn-1" {
+		t.Errorf("outline = %+v", draft.Outline)
+	}
+	if !strings.Contains(draft.Markdown, "[1]") ||
+		!strings.Contains(draft.Markdown, "## Sources\n\n- [1] [Go 1.30 ships arenas](https://example.com/arenas)") {
+		t.Errorf("markdown lacks citations:\n%s", draft.Markdown)
+	}
+
+	calls := provider.Calls()
+	if len(calls) != 2 || !strings.Contains(calls[0].Messages[1].Content, "free memory in bulk") {
+		t.Errorf("outline prompt does not include the article text")
+	}
+
+	data, err := os.ReadFile(draft.Path)
+	if err != nil {
+		t.Fatalf("draft not saved: %v", err)
+	}
+	if !strings.Contains(string(data), `title: "`+draft.Title+`"`) || !strings.HasSuffix(string(data), draft.Markdown) {
+		t.Errorf("saved draft:\n%s", data)
+	}
+}
+
+func TestDraftWithoutSources(t *testing.T) {
+	svc := service.NewAgentService(
+		llm.NewAgent(fake.New()),
+		newTrendService(t, entity.NewTrend("hn-1", "Not starred", "")),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+	)
+
+	_, err := svc.Draft(context.Background(), service.DraftRequest{})
+	if !errors.Is(err, domain.ErrNoDraftSources) {
+		t.Errorf("err = %v, want ErrNoDraftSources", err)
+	}
+}
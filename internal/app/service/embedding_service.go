@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+// Embedder turns texts into vectors. Name identifies the model, so vectors
+// from different embedders are never compared.
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbeddingRepository stores trend vectors per embedder, keyed by trend ID.
+type EmbeddingRepository interface {
+	LoadEmbeddings(ctx context.Context, embedder string) (map[string][]float32, error)
+	SaveEmbeddings(ctx context.Context, embedder string, vectors map[string][]float32) error
+}
+
+// EmbedBatchSize is how many texts are sent to the embedder at once.
+const EmbedBatchSize = 64
+
+// ScoredTrend is a trend and its cosine similarity to a query or another
+// trend.
+type ScoredTrend struct {
+	Trend      *entity.Trend
+	Similarity float64
+}
+
+type EmbeddingService struct {
+	embedder Embedder
+	repo     EmbeddingRepository
+	trendSvc *TrendService
+	mu       sync.Mutex
+}
+
+func NewEmbeddingService(embedder Embedder, repo EmbeddingRepository, trendSvc *TrendService) *EmbeddingService {
+	return &EmbeddingService{embedder: embedder, repo: repo, trendSvc: trendSvc}
+}
+
+func (s *EmbeddingService) Name() string {
+	return "embedding"
+}
+
+func (s *EmbeddingService) Process(ctx context.Context, profile string, result *entity.CollectionResult) error {
+	_, err := s.Index(ctx, result.Trends)
+	return err
+}
+
+// Index embeds the trends that have no stored vector yet and returns how
+// many were added.
+func (s *EmbeddingService) Index(ctx context.Context, trends []*entity.Trend) (int, error) {
+	_, added, err := s.vectors(ctx, trends)
+	return added, err
+}
+
+// Related returns the trends most similar to the one with id.
+func (s *EmbeddingService) Related(ctx context.Context, id string, limit int) ([]ScoredTrend, error) {
+	target, err := s.trendSvc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	trends, vectors, err := s.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rank(vectors[target.ID()], trends, vectors, target.ID(), limit), nil
+}
+
+// Search ranks stored trends by similarity to query.
+func (s *EmbeddingService) Search(ctx context.Context, query string, limit int) ([]ScoredTrend, error) {
+	trends, vectors, err := s.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	return rank(q[0], trends, vectors, "", limit), nil
+}
+
+// Vectors returns the stored vectors of trends, embedding any that are
+// missing.
+func (s *EmbeddingService) Vectors(ctx context.Context, trends []*entity.Trend) (map[string][]float32, error) {
+	vectors, _, err := s.vectors(ctx, trends)
+	return vectors, err
+}
+
+func (s *EmbeddingService) all(ctx context.Context) ([]*entity.Trend, map[string][]float32, error) {
+	all, _, err := s.trendSvc.List(ctx, ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A trend collected on several days is ranked once, as last seen.
+	index := make(map[string]int, len(all))
+	trends := make([]*entity.Trend, 0, len(all))
+	for _, t := range all {
+		if i, ok := index[t.ID()]; ok {
+			if t.CollectedAt().After(trends[i].CollectedAt()) {
+				trends[i] = t
+			}
+			continue
+		}
+		index[t.ID()] = len(trends)
+		trends = append(trends, t)
+	}
+
+	vectors, _, err := s.vectors(ctx, trends)
+	return trends, vectors, err
+}
+
+func (s *EmbeddingService) vectors(ctx context.Context, trends []*entity.Trend) (map[string][]float32, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := s.embedder.Name()
+	vectors, err := s.repo.LoadEmbeddings(ctx, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if vectors == nil {
+		vectors = make(map[string][]float32)
+	}
+
+	var pending []*entity.Trend
+	for _, t := range trends {
+		if _, ok := vectors[t.ID()]; !ok {
+			pending = append(pending, t)
+		}
+	}
+	if len(pending) == 0 {
+		return vectors, 0, nil
+	}
+
+	added := 0
+	for start := 0; start < len(pending); start += EmbedBatchSize {
+		batch := pending[start:min(start+EmbedBatchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for i, t := range batch {
+			texts[i] = EmbeddingText(t)
+		}
+
+		embedded, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			// Keep what was embedded so far rather than redoing it.
+			if added > 0 {
+				s.repo.SaveEmbeddings(ctx, name, vectors)
+			}
+			return nil, 0, err
+		}
+		for i, t := range batch {
+			vectors[t.ID()] = embedded[i]
+		}
+		added += len(batch)
+	}
+
+	if err := s.repo.SaveEmbeddings(ctx, name, vectors); err != nil {
+		return nil, 0, err
+	}
+	return vectors, added, nil
+}
+
+// EmbeddingText is what gets embedded for a trend: its title, latest
+// summary and tags.
+func EmbeddingText(t *entity.Trend) string {
+	parts := []string{t.Title()}
+	if latest := t.LatestSummary(); latest != nil {
+		parts = append(parts, latest.Text)
+	} else if t.Summary() != "" {
+		parts = append(parts, t.Summary())
+	}
+	if len(t.Tags()) > 0 {
+		parts = append(parts, strings.Join(t.Tags(), ", "))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func rank(query []float32, trends []*entity.Trend, vectors map[string][]float32, exclude string, limit int) []ScoredTrend {
+	if query == nil {
+		return nil
+	}
+
+	var scored []ScoredTrend
+	for _, t := range trends {
+		if t.ID() == exclude {
+			continue
+		}
+		sim := Cosine(query, vectors[t.ID()])
+		if sim <= 0 {
+			continue
+		}
+		scored = append(scored, ScoredTrend{Trend: t, Similarity: sim})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Similarity > scored[j].Similarity
+	})
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if they differ in
+// length or either is zero.
+func Cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/embedding"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestRelatedTrends(t *testing.T) {
+	ctx := context.Background()
+	trendSvc := newTrendService(t,
+		entity.NewTrend("hn-1", "Kubernetes 1.34 released with sidecar containers GA", ""),
+		entity.NewTrend("hn-2", "What's new in Kubernetes 1.34: sidecar containers", ""),
+		entity.NewTrend("gh-1", "kubernetes/kubernetes v1.34.0 release", ""),
+		entity.NewTrend("hn-3", "A history of the pocket calculator", ""),
+	)
+
+	svc := service.NewEmbeddingService(embedding.NewHashed(0), markdown.NewEmbeddingRepository(t.TempDir()), trendSvc)
+
+	related, err := svc.Related(ctx, "hn-1", 2)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(related) != 2 {
+		t.Fatalf("related = %d trends, want 2", len(related))
+	}
+	for _, r := range related {
+		if r.Trend.ID() == "hn-3" || r.Trend.ID() == "hn-1" {
+			t.Errorf("unexpected related trend %s (%.3f)", r.Trend.ID(), r.Similarity)
+		}
+	}
+
+	// Vectors are stored, so a second run embeds nothing new.
+	added, err := svc.Index(ctx, []*entity.Trend{entity.NewTrend("hn-1", "ignored", "")})
+	if err != nil || added != 0 {
+		t.Errorf("Index = %d, %v; want 0 new vectors", added, err)
+	}
+
+	found, err := svc.Search(ctx, "pocket calculators history", 1)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(found) != 1 || found[0].Trend.ID() != "hn-3" {
+		t.Errorf("search = %+v, want hn-3", found)
+	}
+}
+
+func TestRelatedListsTrendsOnce(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := markdown.NewTrendRepositoryAdapter(dir)
+	trends := []*entity.Trend{
+		entity.NewTrend("hn-1", "Kubernetes 1.34 released with sidecar containers GA", ""),
+		entity.NewTrend("hn-2", "What's new in Kubernetes 1.34: sidecar containers", ""),
+		entity.NewTrend("gh-1", "kubernetes/kubernetes v1.34.0 release", ""),
+	}
+
+	// Store the same trends yesterday and again today, as a re-collection does.
+	if err := repo.SaveBatch(ctx, trends); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	trendsDir := filepath.Join(dir, "tech", "trends")
+	today := time.Now().Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	if err := os.Rename(filepath.Join(trendsDir, today+".md"), filepath.Join(trendsDir, yesterday+".md")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := repo.SaveBatch(ctx, trends); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+
+	svc := service.NewEmbeddingService(embedding.NewHashed(0), markdown.NewEmbeddingRepository(t.TempDir()), service.NewTrendService(repo))
+
+	related, err := svc.Related(ctx, "hn-1", 2)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	found, err := svc.Search(ctx, "kubernetes sidecar containers", 3)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	for name, results := range map[string][]service.ScoredTrend{"related": related, "search": found} {
+		seen := make(map[string]bool)
+		for _, r := range results {
+			if seen[r.Trend.ID()] {
+				t.Errorf("%s lists %s twice", name, r.Trend.ID())
+			}
+			seen[r.Trend.ID()] = true
+		}
+	}
+	if len(related) != 2 || len(found) != 3 {
+		t.Errorf("related = %d, search = %d trends; want 2 and 3 distinct", len(related), len(found))
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+// MetaEnrichedAt marks trends that the enrichment stage has processed.
+const MetaEnrichedAt = "enriched_at"
+
+type EnrichmentConfig struct {
+	Summarize   bool
+	Tag         bool
+	Concurrency int
+	// MaxPerRun caps how many trends are sent to the LLM per collection.
+	MaxPerRun int
+}
+
+// EnrichmentService summarises and tags newly collected trends. It runs as a
+// collection stage so every run leaves trends ready for browsing.
+type EnrichmentService struct {
+	agentSvc *AgentService
+	repo     TrendRepository
+	cfg      EnrichmentConfig
+}
+
+func NewEnrichmentService(agentSvc *AgentService, repo TrendRepository, cfg EnrichmentConfig) *EnrichmentService {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	return &EnrichmentService{
+		agentSvc: agentSvc,
+		repo:     repo,
+		cfg:      cfg,
+	}
+}
+
+func (s *EnrichmentService) Name() string {
+	return "enrichment"
+}
+
+func (s *EnrichmentService) Process(ctx context.Context, profile string, result *entity.CollectionResult) error {
+	_, err := s.Enrich(ctx, result.Trends)
+	return err
+}
+
+// Enrich processes trends that have not been enriched yet, up to the per-run
+// budget, and persists the results. It returns the number of trends updated.
+func (s *EnrichmentService) Enrich(ctx context.Context, trends []*entity.Trend) (int, error) {
+	var pending []*entity.Trend
+	for _, t := range trends {
+		if IsEnriched(t) {
+			continue
+		}
+		if s.cfg.MaxPerRun > 0 && len(pending) >= s.cfg.MaxPerRun {
+			break
+		}
+		pending = append(pending, t)
+	}
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	// Background enrichment stops once the budget is spent, in either mode,
+	// so what is left goes to interactive requests.
+	if s.agentSvc.usage != nil {
+		status, err := s.agentSvc.usage.Budget(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if status.Exceeded {
+			return 0, fmt.Errorf("skipped %d trends: %w", len(pending), domain.ErrBudgetExceeded)
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		done []*entity.Trend
+		sem  = make(chan struct{}, s.cfg.Concurrency)
+	)
+
+	for _, t := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t *entity.Trend) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := s.enrichTrend(ctx, t)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", t.ID(), err))
+				return
+			}
+			done = append(done, t)
+		}(t)
+	}
+	wg.Wait()
+
+	for _, t := range done {
+		if err := s.repo.Update(ctx, t); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.ID(), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return len(done), fmt.Errorf("enrichment failed for %d of %d trends: %w", len(errs), len(pending), errs[0])
+	}
+	return len(done), nil
+}
+
+func (s *EnrichmentService) enrichTrend(ctx context.Context, t *entity.Trend) error {
+	if s.cfg.Summarize && t.LatestSummary() == nil {
+		rec, err := s.agentSvc.SummarizeTrend(ctx, t)
+		if err != nil {
+			return err
+		}
+		t.AddSummary(*rec, s.agentSvc.summaryHistory)
+	}
+
+	if s.cfg.Tag {
+		tags, err := s.agentSvc.Tag(ctx, t)
+		if err != nil {
+			return err
+		}
+		if tags.Category != "" {
+			t.SetCategory(tags.Category)
+		}
+		t.SetTags(mergeTags(t.Tags(), tags.Tags))
+	}
+
+	t.SetMetadata(MetaEnrichedAt, time.Now().UTC().Format(time.RFC3339))
+	return nil
+}
+
+func IsEnriched(t *entity.Trend) bool {
+	_, ok := t.Metadata()[MetaEnrichedAt]
+	return ok
+}
+
+func mergeTags(existing, add []string) []string {
+	seen := make(map[string]bool, len(existing)+len(add))
+	merged := make([]string, 0, len(existing)+len(add))
+	for _, batch := range [][]string{existing, add} {
+		for _, tag := range batch {
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestEnrichSummarizesAndTagsOnce(t *testing.T) {
+	ctx := context.Background()
+	trend := entity.NewTrend("hn-3", "Postgres 18 adds async I/O", "")
+	trend.SetTags([]string{"hn"})
+
+	repo := markdown.NewTrendRepositoryAdapter(t.TempDir())
+	if err := repo.SaveBatch(ctx, []*entity.Trend{trend}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+
+	provider := fake.New()
+	agentSvc := service.NewAgentService(
+		llm.NewAgent(provider),
+		service.NewTrendService(repo),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+	)
+	enricher := service.NewEnrichmentService(agentSvc, repo, service.EnrichmentConfig{
+		Summarize:   true,
+		Tag:         true,
+		Concurrency: 2,
+	})
+
+	n, err := enricher.Enrich(ctx, []*entity.Trend{trend})
+	if err != nil || n != 1 {
+		t.Fatalf("Enrich = %d, %v", n, err)
+	}
+
+	stored, err := repo.FindByID(ctx, "hn-3")
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if rec := stored.LatestSummary(); rec == nil || rec.Model == "" || rec.PromptVersion == "" {
+		t.Errorf("summary record = %+v, want text with provenance", stored.Summaries())
+	}
+	if !service.IsEnriched(stored) {
+		t.Error("trend not marked as enriched")
+	}
+	if len(stored.Tags()) < 2 || stored.Tags()[0] != "hn" {
+		t.Errorf("tags = %v, want collector tags kept and LLM tags added", stored.Tags())
+	}
+
+	calls := len(provider.Calls())
+	if n, err := enricher.Enrich(ctx, []*entity.Trend{stored}); err != nil || n != 0 {
+		t.Fatalf("second Enrich = %d, %v", n, err)
+	}
+	if len(provider.Calls()) != calls {
+		t.Error("enriched trend was sent to the LLM again")
+	}
+}
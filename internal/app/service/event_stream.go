@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"r3f-trends/internal/domain/event"
+)
+
+const (
+	// DefaultEventBacklog is how many recent events are kept for clients
+	// that reconnect with Last-Event-ID.
+	DefaultEventBacklog = 256
+	// eventBuffer is how many events a client may fall behind before it is
+	// dropped; it can reconnect and catch up from the backlog.
+	eventBuffer = 64
+)
+
+// StreamedEvents are the event types an EventStream subscribes to.
+var StreamedEvents = []string{
+	"collection.started",
+	"collection.progress",
+	"collection.completed",
+	"trend.collected",
+	"trend.starred",
+	"alert.raised",
+}
+
+// StreamEvent is an event as sent to stream clients. IDs are unique for the
+// life of the server.
+type StreamEvent struct {
+	ID   string
+	Type string
+	Data []byte
+	seq  uint64
+}
+
+// EventStream fans domain events out to connected clients, such as the
+// server-sent events endpoint, and keeps a backlog so that clients can
+// resume after a reconnect.
+type EventStream struct {
+	mu sync.Mutex
+	// epoch tells IDs of this run apart from those of an earlier one.
+	epoch   string
+	seq     uint64
+	size    int
+	backlog []StreamEvent
+	clients map[chan StreamEvent]bool
+	closed  bool
+}
+
+func NewEventStream(backlog int) *EventStream {
+	if backlog <= 0 {
+		backlog = DefaultEventBacklog
+	}
+	return &EventStream{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:    backlog,
+		clients: make(map[chan StreamEvent]bool),
+	}
+}
+
+// Subscribe forwards the StreamedEvents dispatched on d to the stream, in
+// order if d supports it. Publish does not block, so it is safe to run
+// in the dispatching goroutine.
+func (s *EventStream) Subscribe(d event.EventDispatcher) {
+	for _, t := range StreamedEvents {
+		if sd, ok := d.(event.SyncSubscriber); ok {
+			sd.SubscribeSync(t, s.Publish)
+		} else {
+			d.Subscribe(t, s.Publish)
+		}
+	}
+}
+
+// Publish sends e to every client. A client whose buffer is full is
+// dropped rather than holding up the others.
+func (s *EventStream) Publish(e event.Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		slog.Error("event stream cannot encode event", "event", e.Type(), "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	s.seq++
+	ev := StreamEvent{ID: fmt.Sprintf("%s-%d", s.epoch, s.seq), Type: e.Type(), Data: data, seq: s.seq}
+	s.backlog = append(s.backlog, ev)
+	if len(s.backlog) > s.size {
+		s.backlog = s.backlog[len(s.backlog)-s.size:]
+	}
+
+	for ch := range s.clients {
+		select {
+		case ch <- ev:
+		default:
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Listen registers a client. It returns the backlog events after
+// lastEventID, the channel for new events, which is closed if the client
+// falls behind or the stream closes, and a func to unregister.
+//
+// Without lastEventID nothing is replayed. An ID from an earlier run of
+// the server, or one that has left the backlog, replays the whole backlog.
+func (s *EventStream) Listen(lastEventID string) ([]StreamEvent, <-chan StreamEvent, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan StreamEvent, eventBuffer)
+	if s.closed {
+		close(ch)
+		return nil, ch, func() {}
+	}
+	s.clients[ch] = true
+
+	var replay []StreamEvent
+	if lastEventID != "" {
+		replay = s.since(lastEventID)
+	}
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.clients[ch] {
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+	return replay, ch, cancel
+}
+
+// Close disconnects all clients, e.g. when the server shuts down.
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for ch := range s.clients {
+		delete(s.clients, ch)
+		close(ch)
+	}
+}
+
+// since returns the backlog after lastEventID. Events that have already
+// left the backlog are lost.
+func (s *EventStream) since(lastEventID string) []StreamEvent {
+	epoch, seq, _ := strings.Cut(lastEventID, "-")
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || epoch != s.epoch {
+		n = 0
+	}
+	for i, ev := range s.backlog {
+		if ev.seq > n {
+			return append([]StreamEvent(nil), s.backlog[i:]...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/event"
+)
+
+func TestEventStreamResume(t *testing.T) {
+	stream := service.NewEventStream(2)
+	for _, id := range []string{"a", "b", "c"} {
+		stream.Publish(&event.TrendStarredEvent{TrendID: id, Starred: true})
+	}
+
+	// The backlog holds b and c; a client that saw b gets c.
+	all, _, cancel := stream.Listen("bogus-0")
+	cancel()
+	if len(all) != 2 || !strings.Contains(string(all[0].Data), `"trend_id":"b"`) {
+		t.Fatalf("replay of an unknown ID = %d events, want the backlog", len(all))
+	}
+	replay, events, cancel := stream.Listen(all[0].ID)
+	defer cancel()
+	if len(replay) != 1 || replay[0].ID != all[1].ID {
+		t.Fatalf("replay after %s = %+v, want only %s", all[0].ID, replay, all[1].ID)
+	}
+
+	if fresh, _, cancel := stream.Listen(""); len(fresh) != 0 {
+		t.Errorf("new client got %d replayed events, want none", len(fresh))
+		cancel()
+	}
+
+	stream.Publish(&event.AlertRaisedEvent{Rule: "go-release", TrendID: "d"})
+	ev := <-events
+	if ev.Type != "alert.raised" || ev.ID == all[1].ID {
+		t.Errorf("live event = %s %s", ev.Type, ev.ID)
+	}
+
+	stream.Close()
+	if _, ok := <-events; ok {
+		t.Error("events still open after Close")
+	}
+}
+
+func TestEventStreamDropsSlowClients(t *testing.T) {
+	stream := service.NewEventStream(0)
+	_, events, cancel := stream.Listen("")
+	defer cancel()
+
+	for i := 0; i < 100; i++ {
+		stream.Publish(&event.CollectionProgressEvent{Done: i})
+	}
+	n := 0
+	for range events {
+		n++
+	}
+	if n == 0 || n == 100 {
+		t.Errorf("slow client got %d events before being dropped", n)
+	}
+}
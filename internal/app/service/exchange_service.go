@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/app/exchange"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/valueobject"
+)
+
+// SourceStore reads a profile's sources and adds imported ones.
+type SourceStore interface {
+	LoadByProfile(ctx context.Context, profile string) ([]*entity.Source, error)
+	AppendSources(ctx context.Context, profile string, sources []*entity.Source) error
+}
+
+// ExportRequest selects the trends of a profile collected between From and
+// To (YYYY-MM-DD, both optional and inclusive).
+type ExportRequest struct {
+	Profile string
+	From    string
+	To      string
+}
+
+// ImportResult counts what an import added and what it merged into
+// existing entries.
+type ImportResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped,omitempty"`
+}
+
+// ExchangeService moves trends and feed subscriptions in and out of the
+// store.
+type ExchangeService struct {
+	trendRepo TrendRepository
+	profiles  ProfileLoader
+	sources   SourceStore
+	now       func() time.Time
+}
+
+func NewExchangeService(trendRepo TrendRepository, profiles ProfileLoader, sources SourceStore) *ExchangeService {
+	return &ExchangeService{trendRepo: trendRepo, profiles: profiles, sources: sources, now: time.Now}
+}
+
+// Export calls fn for each trend of req, oldest first. The request is
+// checked before the first call, so an error without calls means nothing
+// was exported.
+func (s *ExchangeService) Export(ctx context.Context, req ExportRequest, fn func(t *entity.Trend) error) error {
+	profile, err := s.profiles.Load(ctx, req.Profile)
+	if err != nil {
+		return err
+	}
+	for _, date := range []string{req.From, req.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+		}
+	}
+
+	stored, _, err := s.trendRepo.List(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	sources := profileSources(profile)
+	latest := make(map[string]*entity.Trend)
+	for _, t := range stored {
+		if len(sources) > 0 && t.SourceID() != "" && !sources[t.SourceID()] {
+			continue
+		}
+		date := t.CollectedAt().Format("2006-01-02")
+		if (req.From != "" && date < req.From) || (req.To != "" && date > req.To) {
+			continue
+		}
+		// A trend collected on several days is exported once, as last seen.
+		if prev, ok := latest[t.ID()]; !ok || t.CollectedAt().After(prev.CollectedAt()) {
+			latest[t.ID()] = t
+		}
+	}
+
+	trends := make([]*entity.Trend, 0, len(latest))
+	for _, t := range latest {
+		trends = append(trends, t)
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		if !trends[i].CollectedAt().Equal(trends[j].CollectedAt()) {
+			return trends[i].CollectedAt().Before(trends[j].CollectedAt())
+		}
+		return trends[i].ID() < trends[j].ID()
+	})
+
+	for _, t := range trends {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import merges trends into the store by ID. For trends already stored,
+// stars, tags, summaries and metadata from both sides are kept, so a lossy
+// CSV import does not drop LLM output.
+func (s *ExchangeService) Import(ctx context.Context, trends []*entity.Trend) (*ImportResult, error) {
+	stored, _, err := s.trendRepo.List(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*entity.Trend, len(stored))
+	for _, t := range stored {
+		existing[t.ID()] = t
+	}
+
+	result := &ImportResult{}
+	unique := make([]*entity.Trend, 0, len(trends))
+	seen := make(map[string]int, len(trends))
+	for _, t := range trends {
+		if t.CollectedAt().IsZero() {
+			t = withCollectedAt(t, s.now())
+		}
+		if old, ok := existing[t.ID()]; ok {
+			mergeImported(t, old)
+		}
+		// The last copy of a trend in the input wins.
+		if i, ok := seen[t.ID()]; ok {
+			unique[i] = t
+			continue
+		}
+		seen[t.ID()] = len(unique)
+		unique = append(unique, t)
+	}
+
+	added, err := s.trendRepo.MergeBatch(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	result.Added = added
+	result.Updated = len(unique) - added
+	return result, nil
+}
+
+// Subscriptions lists the profile's feed sources for an OPML export.
+func (s *ExchangeService) Subscriptions(ctx context.Context, profile string) ([]exchange.Subscription, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return nil, err
+	}
+	sources, err := s.sources.LoadByProfile(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []exchange.Subscription
+	for _, src := range sources {
+		if src.Type() != valueobject.CollectorTypeRSS.String() {
+			continue
+		}
+		feedURL, _ := src.Config()["url"].(string)
+		if feedURL == "" {
+			continue
+		}
+		siteURL, _ := src.Config()["site_url"].(string)
+		subs = append(subs, exchange.Subscription{Title: src.Name(), FeedURL: feedURL, SiteURL: siteURL})
+	}
+	return subs, nil
+}
+
+// Subscribe adds feed sources for subs whose feed URL the profile does not
+// have yet. There is no collector for feeds yet, so they are added
+// disabled.
+func (s *ExchangeService) Subscribe(ctx context.Context, profile string, subs []exchange.Subscription) (*ImportResult, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return nil, err
+	}
+	sources, err := s.sources.LoadByProfile(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(sources))
+	feeds := make(map[string]bool, len(sources))
+	for _, src := range sources {
+		ids[src.ID()] = true
+		if u, ok := src.Config()["url"].(string); ok {
+			feeds[u] = true
+		}
+	}
+
+	result := &ImportResult{}
+	var added []*entity.Source
+	for _, sub := range subs {
+		if feeds[sub.FeedURL] {
+			result.Skipped++
+			continue
+		}
+		feeds[sub.FeedURL] = true
+
+		id := sourceID(sub.Title, ids)
+		ids[id] = true
+		src := entity.NewSource(id, sub.Title, valueobject.CollectorTypeRSS.String())
+		config := map[string]any{"url": sub.FeedURL}
+		if sub.SiteURL != "" {
+			config["site_url"] = sub.SiteURL
+		}
+		src.SetConfig(config)
+		src.SetEnabled(false)
+		added = append(added, src)
+	}
+
+	if len(added) > 0 {
+		if err := s.sources.AppendSources(ctx, profile, added); err != nil {
+			return nil, err
+		}
+	}
+	result.Added = len(added)
+	return result, nil
+}
+
+// mergeImported carries over from old what t does not have, the way
+// re-collected trends keep their stored state.
+func mergeImported(t, old *entity.Trend) {
+	t.SetStarred(t.Starred() || old.Starred())
+	if t.Category() == "" {
+		t.SetCategory(old.Category())
+	}
+	t.SetTags(mergeTags(old.Tags(), t.Tags()))
+	if len(t.Summaries()) == 0 {
+		t.SetSummaries(old.Summaries())
+	}
+	for k, v := range old.Metadata() {
+		if _, set := t.Metadata()[k]; !set {
+			t.SetMetadata(k, v)
+		}
+	}
+}
+
+func withCollectedAt(t *entity.Trend, at time.Time) *entity.Trend {
+	dto := t.ToDTO()
+	dto.CollectedAt = at
+	return entity.TrendFromDTO(dto)
+}
+
+// sourceID derives a source ID from a feed title that is not in taken.
+func sourceID(title string, taken map[string]bool) string {
+	base := "feed-" + strings.ReplaceAll(entity.NormalizeTitle(title), " ", "-")
+	if base == "feed-" {
+		base = "feed"
+	}
+	id := base
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
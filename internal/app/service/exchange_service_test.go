@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/exchange"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	profiles := yaml.NewProfileLoader("../../../config/profiles")
+
+	src := markdown.NewTrendRepositoryAdapter(t.TempDir())
+	summarised := entity.NewTrend("hn-1", "Go 1.25 released", "https://go.dev/blog/go1.25")
+	summarised.SetTags([]string{"go"})
+	summarised.AddSummary(entity.SummaryRecord{Text: "A new GC."}, 1)
+	if err := src.SaveBatch(ctx, []*entity.Trend{summarised, entity.NewTrend("hn-2", "Ask HN: editors", "")}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc, _ := exchange.NewEncoder(exchange.CSV, &buf)
+	err := service.NewExchangeService(src, profiles, nil).Export(ctx, service.ExportRequest{Profile: "tech"}, func(t *entity.Trend) error {
+		return enc.Encode(t.ToDTO())
+	})
+	if err != nil || enc.Flush() != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	// The destination already has hn-1 with its summary, collected last week.
+	dst := markdown.NewTrendRepositoryAdapter(t.TempDir())
+	old := entity.TrendFromDTO(&entity.TrendDTO{
+		ID: "hn-1", Title: "Go 1.25 released", Starred: true, Tags: []string{"release"},
+		CollectedAt: time.Now().AddDate(0, 0, -7),
+		Summaries:   []entity.SummaryRecord{{Text: "A new GC."}},
+	})
+	if _, err := dst.MergeBatch(ctx, []*entity.Trend{old}); err != nil {
+		t.Fatalf("MergeBatch: %v", err)
+	}
+
+	var trends []*entity.Trend
+	if err := exchange.Decode(exchange.CSV, &buf, func(dto *entity.TrendDTO) error {
+		trends = append(trends, entity.TrendFromDTO(dto))
+		return nil
+	}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	result, err := service.NewExchangeService(dst, profiles, nil).Import(ctx, trends)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Added != 1 || result.Updated != 1 {
+		t.Errorf("result = %+v, want 1 added and 1 updated", result)
+	}
+
+	stored, total, _ := dst.List(ctx, service.ListOptions{})
+	if total != 2 {
+		t.Fatalf("stored %d trends, want 2 (hn-1 kept in its day file)", total)
+	}
+	for _, s := range stored {
+		if s.ID() != "hn-1" {
+			continue
+		}
+		if !s.Starred() || s.LatestSummary() == nil || strings.Join(s.Tags(), ",") != "release,go" {
+			t.Errorf("merged hn-1 = starred %v, summary %v, tags %v", s.Starred(), s.LatestSummary(), s.Tags())
+		}
+	}
+}
+
+type memorySources struct {
+	sources []*entity.Source
+}
+
+func (m *memorySources) LoadByProfile(ctx context.Context, profile string) ([]*entity.Source, error) {
+	return m.sources, nil
+}
+
+func (m *memorySources) AppendSources(ctx context.Context, profile string, sources []*entity.Source) error {
+	m.sources = append(m.sources, sources...)
+	return nil
+}
+
+func TestOPMLSubscribe(t *testing.T) {
+	ctx := context.Background()
+	opml := `<?xml version="1.0"?>
+<opml version="1.0"><head><title>Reader</title></head><body>
+  <outline text="Go">
+    <outline text="The Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+  </outline>
+  <outline text="The Go Blog" xmlUrl="https://go.dev/blog/feed.atom"/>
+</body></opml>`
+
+	subs, err := exchange.ReadOPML(strings.NewReader(opml))
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("ReadOPML = %v, %v", subs, err)
+	}
+
+	store := &memorySources{}
+	svc := service.NewExchangeService(nil, yaml.NewProfileLoader("../../../config/profiles"), store)
+	result, err := svc.Subscribe(ctx, "tech", subs)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if result.Added != 1 || result.Skipped != 1 {
+		t.Errorf("result = %+v, want the duplicate feed skipped", result)
+	}
+	if src := store.sources[0]; src.ID() != "feed-the-go-blog" || src.Type() != "rss" || src.Enabled() {
+		t.Errorf("source = %s %s enabled=%v", src.ID(), src.Type(), src.Enabled())
+	}
+
+	exported, err := svc.Subscriptions(ctx, "tech")
+	if err != nil || len(exported) != 1 || exported[0].SiteURL != "https://go.dev/blog" {
+		t.Errorf("Subscriptions = %+v, %v", exported, err)
+	}
+}
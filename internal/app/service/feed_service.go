@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/app/feed"
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// DefaultFeedLimit is how many items a feed lists unless asked for more.
+	DefaultFeedLimit = 50
+	// MaxFeedLimit caps the limit a feed reader can ask for.
+	MaxFeedLimit = 500
+)
+
+// FeedRequest selects the trends of a profile's feed. Source matches a
+// source ID or name and Tag a tag, both ignoring case.
+type FeedRequest struct {
+	Profile  string
+	Starred  bool
+	Source   string
+	Tag      string
+	MinScore int
+	Limit    int
+}
+
+// FeedService publishes stored trends as feeds, newest first.
+type FeedService struct {
+	trendSvc *TrendService
+	profiles ProfileLoader
+	now      func() time.Time
+}
+
+func NewFeedService(trendSvc *TrendService, profiles ProfileLoader) *FeedService {
+	return &FeedService{trendSvc: trendSvc, profiles: profiles, now: time.Now}
+}
+
+// Feed returns the feed for req. Its links are left for the caller, which
+// knows the URL it is served at.
+func (s *FeedService) Feed(ctx context.Context, req FeedRequest) (*feed.Feed, error) {
+	profile, err := s.profiles.Load(ctx, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	trends, _, err := s.trendSvc.List(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := profileSources(profile)
+	latest := make(map[string]*entity.Trend)
+	for _, t := range trends {
+		if len(sources) > 0 && t.SourceID() != "" && !sources[t.SourceID()] {
+			continue
+		}
+		if !feedMatches(t, req) {
+			continue
+		}
+		// A trend collected on several days is listed once, as last seen.
+		if prev, ok := latest[t.ID()]; !ok || t.CollectedAt().After(prev.CollectedAt()) {
+			latest[t.ID()] = t
+		}
+	}
+
+	items := make([]feed.Item, 0, len(latest))
+	for _, t := range latest {
+		items = append(items, feedItem(t))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Published.Equal(items[j].Published) {
+			return items[i].Published.After(items[j].Published)
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultFeedLimit
+	}
+	if limit > MaxFeedLimit {
+		limit = MaxFeedLimit
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	f := &feed.Feed{
+		ID:          "urn:r3f-trends:feed:" + profile.Name(),
+		Title:       profile.DisplayName() + " trends",
+		Description: profile.Description(),
+		Items:       items,
+	}
+	for _, item := range items {
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+	}
+	if f.Updated.IsZero() {
+		f.Updated = s.now().UTC()
+	}
+	return f, nil
+}
+
+func feedMatches(t *entity.Trend, req FeedRequest) bool {
+	if req.Starred && !t.Starred() {
+		return false
+	}
+	if t.Score() < req.MinScore {
+		return false
+	}
+	if req.Source != "" && !strings.EqualFold(t.SourceID(), req.Source) && !strings.EqualFold(t.Source(), req.Source) {
+		return false
+	}
+	if req.Tag != "" {
+		for _, tag := range t.Tags() {
+			if strings.EqualFold(tag, req.Tag) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// feedItem uses the latest LLM summary as content, falling back to the
+// collected summary. The item is updated when it was last summarised.
+func feedItem(t *entity.Trend) feed.Item {
+	item := feed.Item{
+		ID:        feed.GUID(t.ID()),
+		Title:     t.Title(),
+		URL:       t.URL(),
+		Content:   strings.TrimSpace(t.Summary()),
+		Author:    t.Author(),
+		Source:    t.Source(),
+		Tags:      t.Tags(),
+		Published: t.Timestamp(),
+		Updated:   t.CollectedAt(),
+	}
+	if item.Published.IsZero() {
+		item.Published = t.CollectedAt()
+	}
+	if latest := t.LatestSummary(); latest != nil {
+		item.Content = strings.TrimSpace(latest.Text)
+		if latest.CreatedAt.After(item.Updated) {
+			item.Updated = latest.CreatedAt
+		}
+	}
+	if item.Updated.Before(item.Published) {
+		item.Updated = item.Published
+	}
+	return item
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/feed"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestFeedFiltersAndFormats(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	release := entity.NewTrend("hn-1", "Go 1.25 released", "https://go.dev/blog/go1.25")
+	release.SetScore(400)
+	release.SetSource("Hacker News")
+	release.SetTags([]string{"go", "release"})
+	release.SetSummary("Collected summary.")
+	release.SetStarred(true)
+	release.AddSummary(entity.SummaryRecord{Text: "Go 1.25 ships a new GC & faster maps."}, 1)
+	low := entity.NewTrend("hn-2", "Ask HN: favourite editor?", "")
+	low.SetScore(10)
+	low.SetSource("Hacker News")
+	low.SetTags([]string{"go"})
+
+	repo := markdown.NewTrendRepositoryAdapter(dir)
+	if err := repo.SaveBatch(ctx, []*entity.Trend{release, low}); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	svc := service.NewFeedService(service.NewTrendService(repo), yaml.NewProfileLoader("../../../config/profiles"))
+
+	f, err := svc.Feed(ctx, service.FeedRequest{Profile: "tech", Source: "hacker news", Tag: "GO", MinScore: 100})
+	if err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	if len(f.Items) != 1 || f.Items[0].ID != feed.GUID("hn-1") {
+		t.Fatalf("items = %+v, want only hn-1", f.Items)
+	}
+	if f.Items[0].Content != "Go 1.25 ships a new GC & faster maps." {
+		t.Errorf("content = %q, want the latest summary", f.Items[0].Content)
+	}
+
+	if f, _ := svc.Feed(ctx, service.FeedRequest{Profile: "tech", Starred: true}); len(f.Items) != 1 {
+		t.Errorf("starred feed has %d items, want 1", len(f.Items))
+	}
+
+	atom, err := feed.Atom(f)
+	if err != nil {
+		t.Fatalf("Atom: %v", err)
+	}
+	var entries struct {
+		IDs []string `xml:"entry>id"`
+	}
+	if err := xml.Unmarshal(atom, &entries); err != nil || len(entries.IDs) != 1 || entries.IDs[0] != "urn:r3f-trends:trend:hn-1" {
+		t.Errorf("atom entries = %v, %v:\n%s", entries.IDs, err, atom)
+	}
+
+	rss, err := feed.RSS(f)
+	if err != nil {
+		t.Fatalf("RSS: %v", err)
+	}
+	if !strings.Contains(string(rss), `<guid isPermaLink="false">urn:r3f-trends:trend:hn-1</guid>`) ||
+		!strings.Contains(string(rss), "new GC &amp; faster maps") {
+		t.Errorf("rss:\n%s", rss)
+	}
+
+	body, err := feed.JSON(f)
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	var doc struct {
+		Items []struct {
+			ID          string `json:"id"`
+			ContentText string `json:"content_text"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil || len(doc.Items) != 1 || doc.Items[0].ID != feed.GUID("hn-1") {
+		t.Errorf("json feed = %+v, %v", doc, err)
+	}
+
+	if _, err := svc.Feed(ctx, service.FeedRequest{Profile: "nope"}); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("err = %v, want ErrProfileNotFound", err)
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHealthTTL is how long check results are reused, so that probes
+	// do not launch a browser or call the LLM API on every request.
+	DefaultHealthTTL = 30 * time.Second
+	healthTimeout    = 10 * time.Second
+)
+
+const (
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+	HealthDisabled    = "disabled"
+)
+
+// HealthCheck is a dependency of the server. If a Required check fails the
+// server is not ready; other failures only degrade it.
+type HealthCheck struct {
+	Name     string
+	Required bool
+	// Detail describes the dependency, e.g. the configured LLM.
+	Detail string
+	// Check is nil for dependencies that are not configured.
+	Check func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Health is the state of the server and its dependencies.
+type Health struct {
+	Status    string                  `json:"status"`
+	Checks    map[string]CheckResult  `json:"checks"`
+	Sources   map[string]SourceStatus `json:"sources"`
+	CheckedAt time.Time               `json:"checked_at"`
+}
+
+// Ready reports whether every required dependency is available.
+func (h *Health) Ready() bool {
+	return h.Status != HealthUnavailable
+}
+
+type HealthService struct {
+	checks    []HealthCheck
+	collector *CollectorService
+	ttl       time.Duration
+
+	mu   sync.Mutex
+	last *Health
+}
+
+type HealthOption func(*HealthService)
+
+func WithHealthTTL(ttl time.Duration) HealthOption {
+	return func(s *HealthService) {
+		s.ttl = ttl
+	}
+}
+
+// NewHealthService reports on checks and, if collector is set, on the last
+// collection of each source.
+func NewHealthService(collector *CollectorService, checks []HealthCheck, opts ...HealthOption) *HealthService {
+	s := &HealthService{
+		checks:    checks,
+		collector: collector,
+		ttl:       DefaultHealthTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Check runs the checks, or reuses results younger than the TTL. Source
+// statuses are always current. Checks are not cancelled with ctx: their
+// results are shared with later callers, so a prober that hangs up must not
+// leave a cached failure behind.
+func (s *HealthService) Check(ctx context.Context) *Health {
+	s.mu.Lock()
+	if s.last == nil || time.Since(s.last.CheckedAt) >= s.ttl {
+		s.last = s.run(context.WithoutCancel(ctx))
+	}
+	h := *s.last
+	s.mu.Unlock()
+
+	h.Sources = map[string]SourceStatus{}
+	if s.collector != nil {
+		h.Sources = s.collector.SourceStatuses()
+	}
+	return &h
+}
+
+// run runs the checks concurrently.
+func (s *HealthService) run(ctx context.Context) *Health {
+	results := make([]CheckResult, len(s.checks))
+	var wg sync.WaitGroup
+	for i, c := range s.checks {
+		results[i] = CheckResult{Status: HealthDisabled, Detail: c.Detail}
+		if c.Check == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c HealthCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+			defer cancel()
+
+			started := time.Now()
+			err := c.Check(ctx)
+			results[i].DurationMS = time.Since(started).Milliseconds()
+			results[i].Status = HealthOK
+			if err != nil {
+				results[i].Status = HealthUnavailable
+				results[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	h := &Health{
+		Status:    HealthOK,
+		Checks:    make(map[string]CheckResult, len(s.checks)),
+		CheckedAt: time.Now(),
+	}
+	for i, c := range s.checks {
+		h.Checks[c.Name] = results[i]
+		if results[i].Status != HealthUnavailable {
+			continue
+		}
+		if c.Required {
+			h.Status = HealthUnavailable
+		} else if h.Status == HealthOK {
+			h.Status = HealthDegraded
+		}
+	}
+	return h
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/metrics"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/valueobject"
+)
+
+type stubCollector struct {
+	err error
+}
+
+func (c *stubCollector) Type() valueobject.CollectorType { return valueobject.CollectorTypeHTTP }
+func (c *stubCollector) Validate(*entity.Source) error   { return nil }
+func (c *stubCollector) Collect(_ context.Context, source *entity.Source) ([]*entity.Trend, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []*entity.Trend{entity.NewTrend(source.ID()+"-1", "Title", "")}, nil
+}
+
+func TestHealthReportsChecksAndSources(t *testing.T) {
+	ctx := context.Background()
+	collectorSvc := service.NewCollectorService(markdown.NewTrendRepositoryAdapter(t.TempDir()), map[string]interface{}{
+		"ok":     &stubCollector{},
+		"broken": &stubCollector{err: context.DeadlineExceeded},
+	})
+	sources := []*entity.Source{entity.NewSource("good", "Good", "ok"), entity.NewSource("bad", "Bad", "broken")}
+	if _, err := collectorSvc.Collect(ctx, "tech", []string{"good", "bad"}, sources); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	calls := 0
+	chromeErr := errors.New("chrome not found")
+	svc := service.NewHealthService(collectorSvc, []service.HealthCheck{
+		{Name: "storage", Required: true, Check: func(context.Context) error { calls++; return nil }},
+		{Name: "chrome", Check: func(context.Context) error { return chromeErr }},
+		{Name: "llm"},
+	})
+
+	h := svc.Check(ctx)
+	if h.Status != service.HealthDegraded || !h.Ready() {
+		t.Errorf("status = %s, want degraded and ready", h.Status)
+	}
+	if c := h.Checks["chrome"]; c.Status != service.HealthUnavailable || c.Error != chromeErr.Error() {
+		t.Errorf("chrome = %+v", c)
+	}
+	if h.Checks["llm"].Status != service.HealthDisabled {
+		t.Errorf("llm = %+v, want disabled", h.Checks["llm"])
+	}
+	if h.Sources["good"].LastSuccess.IsZero() || h.Sources["good"].LastError != "" {
+		t.Errorf("good = %+v, want a success", h.Sources["good"])
+	}
+	if h.Sources["bad"].LastError == "" || !h.Sources["bad"].LastSuccess.IsZero() {
+		t.Errorf("bad = %+v, want an error", h.Sources["bad"])
+	}
+
+	svc.Check(ctx)
+	if calls != 1 {
+		t.Errorf("storage checked %d times, want results reused", calls)
+	}
+
+	var out strings.Builder
+	metrics.Default.Write(&out)
+	for _, want := range []string{
+		`r3f_collector_errors_total{source="bad",type="timeout"} 1`,
+		`r3f_collected_items_total{source="good"} 1`,
+		`r3f_collection_duration_seconds_count{source="good"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics lack %s", want)
+		}
+	}
+}
+
+func TestHealthUnavailableWhenRequiredCheckFails(t *testing.T) {
+	svc := service.NewHealthService(nil, []service.HealthCheck{
+		{Name: "storage", Required: true, Check: func(context.Context) error { return errors.New("read-only") }},
+	}, service.WithHealthTTL(time.Nanosecond))
+
+	if h := svc.Check(context.Background()); h.Ready() || h.Status != service.HealthUnavailable {
+		t.Errorf("status = %s, want unavailable", h.Status)
+	}
+}
+
+func TestHealthIgnoresCallerCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	svc := service.NewHealthService(nil, []service.HealthCheck{
+		{Name: "storage", Required: true, Check: func(ctx context.Context) error { return ctx.Err() }},
+	})
+	if h := svc.Check(ctx); !h.Ready() || h.Checks["storage"].Status != service.HealthOK {
+		t.Errorf("health after the caller hung up = %+v, want storage ok", h)
+	}
+}
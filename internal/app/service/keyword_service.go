@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// DefaultKeywordsPerTrend is how many keywords are added to each trend's
+	// tags when keywords.per_trend is not set.
+	DefaultKeywordsPerTrend = 5
+	// DefaultKeywordWindow is how many days GET /keywords looks at when no
+	// range is given.
+	DefaultKeywordWindow = 7
+	// MinRisingCount is how many trends of a period must mention a term for
+	// it to be listed as rising.
+	MinRisingCount = 2
+
+	// titleWeight counts title words more than summary words, since titles
+	// say what a trend is about.
+	titleWeight = 2
+	// phraseBoost favours two-word phrases over their words alone.
+	phraseBoost = 1.5
+)
+
+// MetaKeywordsAt marks trends whose keywords have been added to their tags,
+// so that later collections do not pile on more as the corpus changes.
+const MetaKeywordsAt = "keywords_at"
+
+type KeywordConfig struct {
+	PerTrend int
+}
+
+// KeywordTrend is a term and how many trends mentioned it in a period and in
+// the period of the same length before it.
+type KeywordTrend struct {
+	Term     string  `json:"term"`
+	Count    int     `json:"count"`
+	Previous int     `json:"previous"`
+	Growth   float64 `json:"growth"`
+}
+
+// RisingKeywords are the rising terms of the period From to To.
+type RisingKeywords struct {
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Keywords []KeywordTrend `json:"keywords"`
+}
+
+// KeywordService extracts keywords from trend titles and summaries by
+// TF-IDF over all stored trends, so that words common to every trend do
+// not count. It runs as a collection stage, adding the keywords to the
+// tags of new trends.
+type KeywordService struct {
+	trendSvc *TrendService
+	cfg      KeywordConfig
+	now      func() time.Time
+}
+
+func NewKeywordService(trendSvc *TrendService, cfg KeywordConfig) *KeywordService {
+	if cfg.PerTrend <= 0 {
+		cfg.PerTrend = DefaultKeywordsPerTrend
+	}
+	return &KeywordService{
+		trendSvc: trendSvc,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+func (s *KeywordService) Name() string {
+	return "keywords"
+}
+
+func (s *KeywordService) Process(ctx context.Context, profile string, result *entity.CollectionResult) error {
+	_, err := s.Tag(ctx, result.Trends)
+	return err
+}
+
+// Tag adds the keywords of trends that have not been tagged yet to their
+// tags and stores them. It returns the number of trends updated.
+func (s *KeywordService) Tag(ctx context.Context, trends []*entity.Trend) (int, error) {
+	var pending []*entity.Trend
+	for _, t := range trends {
+		if _, ok := t.Metadata()[MetaKeywordsAt]; !ok {
+			pending = append(pending, t)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	corpus, _, err := s.trendSvc.List(ctx, ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	df := documentFrequency(corpus)
+
+	updated := 0
+	for _, t := range pending {
+		t.SetTags(mergeTags(t.Tags(), Keywords(t, df, len(corpus), s.cfg.PerTrend)))
+		t.SetMetadata(MetaKeywordsAt, s.now().UTC().Format(time.RFC3339))
+		if err := s.trendSvc.Update(ctx, t); err != nil {
+			return updated, fmt.Errorf("%s: %w", t.ID(), err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// Rising lists the terms mentioned by at least MinRisingCount trends
+// collected between from and to (inclusive, YYYY-MM-DD), fastest growing
+// first compared with the period of the same length before. Empty dates
+// default to the last DefaultKeywordWindow days.
+func (s *KeywordService) Rising(ctx context.Context, from, to string, limit int) (*RisingKeywords, error) {
+	start, end, err := s.period(from, to)
+	if err != nil {
+		return nil, err
+	}
+	days := int(end.Sub(start).Hours()/24) + 1
+	before := start.AddDate(0, 0, -days)
+
+	trends, _, err := s.trendSvc.List(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]int)
+	previous := make(map[string]int)
+	for _, t := range trends {
+		day, _ := time.Parse("2006-01-02", t.CollectedAt().Format("2006-01-02"))
+		var counts map[string]int
+		switch {
+		case !day.Before(start) && !day.After(end):
+			counts = current
+		case !day.Before(before) && day.Before(start):
+			counts = previous
+		default:
+			continue
+		}
+		for term := range candidates(t) {
+			counts[term]++
+		}
+	}
+
+	rising := []KeywordTrend{}
+	for term, count := range current {
+		if count < MinRisingCount || subsumed(term, count, current) {
+			continue
+		}
+		growth := float64(count) / float64(previous[term]+1)
+		rising = append(rising, KeywordTrend{
+			Term:     term,
+			Count:    count,
+			Previous: previous[term],
+			Growth:   math.Round(growth*100) / 100,
+		})
+	}
+
+	sort.Slice(rising, func(i, j int) bool {
+		if rising[i].Growth != rising[j].Growth {
+			return rising[i].Growth > rising[j].Growth
+		}
+		if rising[i].Count != rising[j].Count {
+			return rising[i].Count > rising[j].Count
+		}
+		return rising[i].Term < rising[j].Term
+	})
+	if limit > 0 && len(rising) > limit {
+		rising = rising[:limit]
+	}
+	return &RisingKeywords{
+		From:     start.Format("2006-01-02"),
+		To:       end.Format("2006-01-02"),
+		Keywords: rising,
+	}, nil
+}
+
+func (s *KeywordService) period(from, to string) (time.Time, time.Time, error) {
+	end, err := time.Parse("2006-01-02", s.now().Format("2006-01-02"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to != "" {
+		if end, err = time.Parse("2006-01-02", to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date: %w", err)
+		}
+	}
+
+	start := end.AddDate(0, 0, -(DefaultKeywordWindow - 1))
+	if from != "" {
+		if start, err = time.Parse("2006-01-02", from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date: %w", err)
+		}
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from %s is after to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return start, end, nil
+}
+
+// Keywords returns the n terms of t with the highest TF-IDF, given the
+// document frequencies df of a corpus of size docs. Words already covered
+// by a chosen phrase are skipped.
+func Keywords(t *entity.Trend, df map[string]int, docs, n int) []string {
+	tf := candidates(t)
+
+	type scored struct {
+		term  string
+		score float64
+	}
+	var ranked []scored
+	for term, count := range tf {
+		// A phrase seen in only one trend is more likely an accident of
+		// wording than a topic.
+		phrase := strings.Contains(term, " ")
+		if phrase && df[term] < 2 {
+			continue
+		}
+		score := float64(count) * (math.Log(float64(docs+1)/float64(df[term]+1)) + 1)
+		if phrase {
+			score *= phraseBoost
+		}
+		ranked = append(ranked, scored{term, score})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].term < ranked[j].term
+	})
+
+	var keywords []string
+	covered := make(map[string]bool)
+	for _, r := range ranked {
+		if len(keywords) == n {
+			break
+		}
+		if covered[r.term] {
+			continue
+		}
+		keywords = append(keywords, r.term)
+		for _, w := range strings.Fields(r.term) {
+			covered[w] = true
+		}
+	}
+	return keywords
+}
+
+// documentFrequency counts how many trends mention each term.
+func documentFrequency(trends []*entity.Trend) map[string]int {
+	df := make(map[string]int)
+	for _, t := range trends {
+		for term := range candidates(t) {
+			df[term]++
+		}
+	}
+	return df
+}
+
+// candidates returns the words and adjacent word pairs of the trend's title
+// and latest summary, with title terms counted titleWeight times.
+func candidates(t *entity.Trend) map[string]int {
+	summary := t.Summary()
+	if latest := t.LatestSummary(); latest != nil {
+		summary = latest.Text
+	}
+
+	counts := make(map[string]int)
+	add := func(text string, weight int) {
+		for _, run := range phrases(text) {
+			for i, w := range run {
+				counts[w] += weight
+				if i > 0 {
+					counts[run[i-1]+" "+w] += weight
+				}
+			}
+		}
+	}
+	add(t.Title(), titleWeight)
+	add(summary, 1)
+	return counts
+}
+
+// subsumed reports whether a single word is only ever mentioned as part of
+// a phrase that is listed as well.
+func subsumed(term string, count int, counts map[string]int) bool {
+	if strings.Contains(term, " ") {
+		return false
+	}
+	for other, n := range counts {
+		if n == count && strings.Contains(other, " ") {
+			for _, w := range strings.Fields(other) {
+				if w == term {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestKeywordsTagAndRise(t *testing.T) {
+	ctx := context.Background()
+	trends := []*entity.Trend{
+		entity.NewTrend("hn-1", "Rust compiler gets faster incremental builds", ""),
+		entity.NewTrend("hn-2", "Why the Rust compiler is slow, and how to fix it", ""),
+		entity.NewTrend("hn-3", "Show HN: a tiny web server", ""),
+		entity.NewTrend("hn-4", "The web is getting slower", ""),
+	}
+	trendSvc := newTrendService(t, trends...)
+	svc := service.NewKeywordService(trendSvc, service.KeywordConfig{PerTrend: 3})
+
+	updated, err := svc.Tag(ctx, trends)
+	if err != nil || updated != 4 {
+		t.Fatalf("Tag = %d, %v", updated, err)
+	}
+
+	stored, err := trendSvc.Get(ctx, "hn-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !slices.Contains(stored.Tags(), "rust compiler") || slices.Contains(stored.Tags(), "rust") {
+		t.Errorf("tags = %v, want the shared phrase instead of its words", stored.Tags())
+	}
+
+	// Tagged trends are left alone on the next collection.
+	if updated, _ := svc.Tag(ctx, []*entity.Trend{stored}); updated != 0 {
+		t.Errorf("retagged %d trends", updated)
+	}
+
+	rising, err := svc.Rising(ctx, "", "", 10)
+	if err != nil {
+		t.Fatalf("Rising: %v", err)
+	}
+	var terms []string
+	for _, k := range rising.Keywords {
+		terms = append(terms, k.Term)
+		if k.Count < service.MinRisingCount || k.Previous != 0 {
+			t.Errorf("keyword %+v", k)
+		}
+	}
+	if !slices.Contains(terms, "rust compiler") || !slices.Contains(terms, "web") || slices.Contains(terms, "rust") {
+		t.Errorf("rising = %v", terms)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"r3f-trends/internal/app/logging"
+)
+
+// Scheduler runs jobs at a fixed interval or daily at a time of day, in the
+// scheduler's time zone. Job errors are logged and the job runs again at
+// its next time.
+type Scheduler struct {
+	loc  *time.Location
+	jobs []scheduledJob
+	now  func() time.Time
+}
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	// hour and minute are used when interval is zero.
+	hour, minute int
+	run          func(ctx context.Context) error
+}
+
+func NewScheduler(loc *time.Location) *Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Scheduler{loc: loc, now: time.Now}
+}
+
+// Every runs job every interval, the first time one interval after Run.
+func (s *Scheduler) Every(name string, interval time.Duration, job func(ctx context.Context) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("schedule %s: interval must be positive", name)
+	}
+	s.jobs = append(s.jobs, scheduledJob{name: name, interval: interval, run: job})
+	return nil
+}
+
+// Daily runs job every day at, given as "15:04".
+func (s *Scheduler) Daily(name, at string, job func(ctx context.Context) error) error {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("schedule %s: %q is not a time of day (HH:MM)", name, at)
+	}
+	s.jobs = append(s.jobs, scheduledJob{name: name, hour: t.Hour(), minute: t.Minute(), run: job})
+	return nil
+}
+
+// Run runs the jobs until ctx is done. Each job runs on its own, so a slow
+// job does not delay the others.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job scheduledJob) {
+			defer wg.Done()
+			ctx := logging.With(ctx, "job", job.name)
+			next := s.next(job, s.now())
+			for {
+				slog.InfoContext(ctx, "scheduled job waiting", "next", next.Format(time.RFC3339))
+				timer := time.NewTimer(next.Sub(s.now()))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+
+				if err := job.run(ctx); err != nil {
+					slog.ErrorContext(ctx, "scheduled job failed", "error", err)
+				}
+				next = s.next(job, s.now())
+			}
+		}(job)
+	}
+	wg.Wait()
+}
+
+// next is the first time job is due after now.
+func (s *Scheduler) next(job scheduledJob, now time.Time) time.Time {
+	if job.interval > 0 {
+		return now.Add(job.interval)
+	}
+	local := now.In(s.loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), job.hour, job.minute, 0, 0, s.loc)
+	if !at.After(local) {
+		at = time.Date(local.Year(), local.Month(), local.Day()+1, job.hour, job.minute, 0, 0, s.loc)
+	}
+	return at
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSchedulerNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	noop := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		at   string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "later today",
+			loc:  time.UTC,
+			at:   "07:30",
+			now:  time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 19, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "exactly now runs tomorrow",
+			loc:  time.UTC,
+			at:   "07:30",
+			now:  time.Date(2026, 10, 19, 7, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 20, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "across the month boundary",
+			loc:  time.UTC,
+			at:   "00:05",
+			now:  time.Date(2026, 10, 31, 23, 59, 0, 0, time.UTC),
+			want: time.Date(2026, 11, 1, 0, 5, 0, 0, time.UTC),
+		},
+		{
+			// 23:30 UTC is already 01:30 the next day in Berlin.
+			name: "day taken in the scheduler's zone",
+			loc:  berlin,
+			at:   "07:00",
+			now:  time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 20, 7, 0, 0, 0, berlin),
+		},
+		{
+			name: "over the end of daylight saving time",
+			loc:  berlin,
+			at:   "07:00",
+			now:  time.Date(2026, 10, 24, 12, 0, 0, 0, berlin),
+			want: time.Date(2026, 10, 25, 6, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(tt.loc)
+			if err := s.Daily("job", tt.at, noop); err != nil {
+				t.Fatalf("Daily: %v", err)
+			}
+			if got := s.next(s.jobs[0], tt.now); !got.Equal(tt.want) {
+				t.Errorf("next = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	s := NewScheduler(time.UTC)
+	s.Every("job", time.Hour, noop)
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+	if got := s.next(s.jobs[0], now); !got.Equal(now.Add(time.Hour)) {
+		t.Errorf("interval next = %v, want an hour after now", got)
+	}
+}
+
+func TestSchedulerRejectsBadSchedules(t *testing.T) {
+	s := NewScheduler(nil)
+	noop := func(context.Context) error { return nil }
+
+	for _, at := range []string{"", "7", "7:30pm", "24:00", "07:60", "07:30:00"} {
+		if err := s.Daily("digest", at, noop); err == nil {
+			t.Errorf("Daily(%q) succeeded", at)
+		}
+	}
+	if err := s.Every("collect", 0, noop); err == nil {
+		t.Error("Every(0) succeeded")
+	}
+	if len(s.jobs) != 0 {
+		t.Errorf("%d jobs added, want none", len(s.jobs))
+	}
+}
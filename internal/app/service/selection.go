@@ -0,0 +1,183 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+const (
+	// DefaultSelectionLimit is how many trends the suggester sees when a
+	// profile does not set selection.limit.
+	DefaultSelectionLimit = 50
+	// DefaultSelectionWindow keeps stale days out of the suggester input when
+	// a profile does not set selection.window.
+	DefaultSelectionWindow = 72 * time.Hour
+
+	keywordBoost = 0.5
+	recencyBoost = 0.5
+)
+
+// SelectedTrend is a trend chosen as suggester input, with the relevance it
+// was ranked by and the profile keywords it matched.
+type SelectedTrend struct {
+	Trend     *entity.Trend `json:"-"`
+	ID        string        `json:"id"`
+	Title     string        `json:"title"`
+	Source    string        `json:"source"`
+	SourceID  string        `json:"source_id,omitempty"`
+	Score     int           `json:"score"`
+	Starred   bool          `json:"starred"`
+	Relevance float64       `json:"relevance"`
+	Keywords  []string      `json:"keywords,omitempty"`
+}
+
+// SelectTrends picks the suggester input for profile from trends. Trends
+// outside the time window, below the minimum score or from sources the
+// profile does not use are dropped. The rest are ranked by popularity,
+// recency and keyword matches, starred trends first if configured, and
+// taken in that order subject to per-source quotas, skipping titles that
+// were already taken.
+func SelectTrends(trends []*entity.Trend, profile *entity.Profile, now time.Time) []SelectedTrend {
+	cfg := profile.Selection()
+
+	window := DefaultSelectionWindow
+	if d, err := time.ParseDuration(cfg.Window); err == nil && d > 0 {
+		window = d
+	}
+	limit := cfg.Limit
+	if limit <= 0 {
+		limit = DefaultSelectionLimit
+	}
+
+	sources := profileSources(profile)
+	keywords := selectionKeywords(profile)
+
+	var candidates []SelectedTrend
+	maxScore := 0
+	for _, t := range trends {
+		age := now.Sub(trendTime(t))
+		if age > window || t.Score() < cfg.MinScore {
+			continue
+		}
+		if len(sources) > 0 && t.SourceID() != "" && !sources[t.SourceID()] {
+			continue
+		}
+
+		candidates = append(candidates, SelectedTrend{
+			Trend:     t,
+			ID:        t.ID(),
+			Title:     t.Title(),
+			Source:    t.Source(),
+			SourceID:  t.SourceID(),
+			Score:     t.Score(),
+			Starred:   t.Starred(),
+			Relevance: recencyBoost * (1 - math.Max(age.Seconds(), 0)/window.Seconds()),
+			Keywords:  matchKeywords(t, keywords),
+		})
+		if t.Score() > maxScore {
+			maxScore = t.Score()
+		}
+	}
+
+	for i := range candidates {
+		c := &candidates[i]
+		if maxScore > 0 && c.Score > 0 {
+			c.Relevance += math.Log1p(float64(c.Score)) / math.Log1p(float64(maxScore))
+		}
+		c.Relevance += keywordBoost * float64(len(c.Keywords))
+		c.Relevance = math.Round(c.Relevance*1000) / 1000
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if cfg.StarredFirst && candidates[i].Starred != candidates[j].Starred {
+			return candidates[i].Starred
+		}
+		return candidates[i].Relevance > candidates[j].Relevance
+	})
+
+	selected := make([]SelectedTrend, 0, limit)
+	titles := make(map[string]bool)
+	perSource := make(map[string]int)
+	for _, c := range candidates {
+		if len(selected) == limit {
+			break
+		}
+
+		title := entity.NormalizeTitle(c.Title)
+		if titles[title] {
+			continue
+		}
+		if quota, ok := cfg.SourceQuotas[c.SourceID]; ok && perSource[c.SourceID] >= quota {
+			continue
+		}
+
+		titles[title] = true
+		perSource[c.SourceID]++
+		selected = append(selected, c)
+	}
+
+	return selected
+}
+
+// trendTime is when the trend was published, or collected if the source
+// did not say.
+func trendTime(t *entity.Trend) time.Time {
+	if !t.Timestamp().IsZero() {
+		return t.Timestamp()
+	}
+	return t.CollectedAt()
+}
+
+// profileSources is the set of source IDs named in the profile's default
+// sources and source groups. An empty set means any source.
+func profileSources(profile *entity.Profile) map[string]bool {
+	sources := make(map[string]bool)
+	for _, s := range profile.DefaultSources() {
+		sources[s] = true
+	}
+	for _, group := range profile.SourceGroups() {
+		for _, s := range group {
+			sources[s] = true
+		}
+	}
+	return sources
+}
+
+// profileFillerWords are words of profile descriptions that, besides the
+// stop words, say nothing about the topics a profile wants.
+var profileFillerWords = map[string]bool{
+	"news": true, "trends": true, "blog": true, "focused": true,
+}
+
+// selectionKeywords are the configured keywords plus the words of the
+// profile description, lowercased.
+func selectionKeywords(profile *entity.Profile) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	add := func(word string) {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] || stopWords[word] || profileFillerWords[word] {
+			return
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+
+	for _, k := range profile.Selection().Keywords {
+		add(k)
+	}
+	for _, word := range splitWords(profile.Description()) {
+		add(word)
+	}
+	return keywords
+}
+
+// matchKeywords returns the keywords found as whole words, or word
+// sequences, in the trend's title, category or tags.
+func matchKeywords(t *entity.Trend, keywords []string) []string {
+	return matchPhrases(t.Title()+" "+t.Category()+" "+strings.Join(t.Tags(), " "), keywords)
+}
@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestSelectTrends(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	trend := func(id, title, sourceID string, score int, age time.Duration) *entity.Trend {
+		tr := entity.NewTrend(id, title, "")
+		tr.SetSourceID(sourceID)
+		tr.SetScore(score)
+		tr.SetTimestamp(now.Add(-age))
+		return tr
+	}
+
+	starred := trend("hn-4", "A quiet release", "hackernews-newest", 20, time.Hour)
+	starred.SetStarred(true)
+
+	trends := []*entity.Trend{
+		trend("hn-1", "Kubernetes 2.0 announced", "hackernews-frontpage", 300, time.Hour),
+		trend("hn-2", "Celebrity gossip", "hackernews-frontpage", 500, time.Hour),
+		trend("hn-3", "Kubernetes 2.0 Announced!", "hackernews-newest", 100, time.Hour),
+		starred,
+		trend("hn-5", "Old news about Rust", "hackernews-frontpage", 900, 10*24*time.Hour),
+		trend("hn-6", "Low score Go post", "hackernews-frontpage", 5, time.Hour),
+		trend("gh-1", "someone/kubernetes-operator", "github-trending-go", 800, time.Hour),
+		trend("x-1", "Rust on another site", "elsewhere", 900, time.Hour),
+	}
+
+	profile := entity.NewProfile("tech", "Tech")
+	profile.SetDescription("Go, Rust, Kubernetes")
+	profile.SetDefaultSources([]string{"hackernews-frontpage", "hackernews-newest", "github-trending-go"})
+	profile.SetSelection(entity.SelectionConfig{
+		Window:       "48h",
+		MinScore:     10,
+		StarredFirst: true,
+		SourceQuotas: map[string]int{"github-trending-go": 0},
+	})
+
+	selected := service.SelectTrends(trends, profile, now)
+
+	var ids []string
+	for _, s := range selected {
+		ids = append(ids, s.ID)
+	}
+	want := []string{"hn-4", "hn-1", "hn-2"}
+	if len(ids) != len(want) {
+		t.Fatalf("selected = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("selected = %v, want %v", ids, want)
+		}
+	}
+	if kw := selected[1].Keywords; len(kw) != 1 || kw[0] != "kubernetes" {
+		t.Errorf("keywords = %v, want [kubernetes]", kw)
+	}
+}
@@ -2,9 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
 
+	"r3f-trends/internal/app/logging"
+	"r3f-trends/internal/app/metrics"
 	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/event"
 	"r3f-trends/internal/domain/valueobject"
 )
 
@@ -27,6 +35,9 @@ type SearchOptions struct {
 
 type TrendRepository interface {
 	SaveBatch(ctx context.Context, trends []*entity.Trend) error
+	// MergeBatch stores trends by ID, keeping the day they were collected
+	// for new ones, and returns how many were new.
+	MergeBatch(ctx context.Context, trends []*entity.Trend) (int, error)
 	List(ctx context.Context, opts ListOptions) ([]*entity.Trend, int, error)
 	FindByID(ctx context.Context, id string) (*entity.Trend, error)
 	FindByDate(ctx context.Context, date string, opts ListOptions) ([]*entity.Trend, int, error)
@@ -41,76 +52,285 @@ type Collector interface {
 	Validate(source *entity.Source) error
 }
 
+// CollectionStage runs after a collection has been saved, e.g. to enrich or
+// index the new trends. Stage errors are reported in the result but do not
+// fail the collection.
+type CollectionStage interface {
+	Name() string
+	Process(ctx context.Context, profile string, result *entity.CollectionResult) error
+}
+
 type CollectorService struct {
 	trendRepo  TrendRepository
 	collectors map[string]Collector
+	stages     []CollectionStage
+	dispatcher event.EventDispatcher
+
+	mu       sync.Mutex
+	statuses map[string]SourceStatus
+}
+
+// SourceStatus is how the last collections of a source went since the
+// server started.
+type SourceStatus struct {
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+}
+
+type CollectorOption func(*CollectorService)
+
+func WithStages(stages ...CollectionStage) CollectorOption {
+	return func(s *CollectorService) {
+		s.stages = append(s.stages, stages...)
+	}
+}
+
+// WithCollectorDispatcher dispatches collection.started and
+// collection.completed events around each collection, collection.progress
+// after each source and trend.collected for each new trend.
+func WithCollectorDispatcher(d event.EventDispatcher) CollectorOption {
+	return func(s *CollectorService) {
+		s.dispatcher = d
+	}
 }
 
-func NewCollectorService(trendRepo TrendRepository, collectors map[string]interface{}) *CollectorService {
+func NewCollectorService(trendRepo TrendRepository, collectors map[string]interface{}, opts ...CollectorOption) *CollectorService {
 	c := make(map[string]Collector)
 	for k, v := range collectors {
 		if col, ok := v.(Collector); ok {
 			c[k] = col
 		}
 	}
-	return &CollectorService{
+	s := &CollectorService{
 		trendRepo:  trendRepo,
 		collectors: c,
+		statuses:   make(map[string]SourceStatus),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *CollectorService) Collect(ctx context.Context, profile string, sourceIDs []string, sources []*entity.Source) (*entity.CollectionResult, error) {
+	started := time.Now()
 	result := &entity.CollectionResult{
+		JobID:  fmt.Sprintf("%s-%s", profile, started.UTC().Format("20060102T150405")),
 		Trends: []*entity.Trend{},
 		Errors: []string{},
 	}
+	ctx = logging.With(ctx, "job_id", result.JobID, "profile", profile)
+	slog.InfoContext(ctx, "collection started", "sources", len(sourceIDs))
+	if s.dispatcher != nil {
+		s.dispatcher.Dispatch(&event.CollectionStartedEvent{
+			JobID:     result.JobID,
+			Profile:   profile,
+			Timestamp: started.UTC().Format(time.RFC3339),
+		})
+	}
 
 	sourceMap := make(map[string]*entity.Source)
 	for _, src := range sources {
 		sourceMap[src.ID()] = src
 	}
 
-	for _, sourceID := range sourceIDs {
-		source, exists := sourceMap[sourceID]
-		if !exists {
-			result.Errors = append(result.Errors, fmt.Sprintf("source not found: %s", sourceID))
-			continue
+	for i, sourceID := range sourceIDs {
+		trends, errMsg := s.collectSource(ctx, sourceMap[sourceID], sourceID)
+		if errMsg != "" {
+			result.Errors = append(result.Errors, errMsg)
 		}
+		result.Trends = append(result.Trends, trends...)
 
-		if !source.Enabled() {
-			continue
+		if s.dispatcher != nil {
+			s.dispatcher.Dispatch(&event.CollectionProgressEvent{
+				JobID:      result.JobID,
+				Profile:    profile,
+				SourceID:   sourceID,
+				Done:       i + 1,
+				Total:      len(sourceIDs),
+				ItemsCount: len(trends),
+				Error:      errMsg,
+				Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			})
 		}
+	}
 
-		collector, exists := s.collectors[source.Type()]
-		if !exists {
-			result.Errors = append(result.Errors, fmt.Sprintf("collector not found for type: %s", source.Type()))
-			continue
+	if len(result.Trends) > 0 {
+		s.carryOver(ctx, result)
+
+		if err := s.trendRepo.SaveBatch(ctx, result.Trends); err != nil {
+			slog.ErrorContext(ctx, "saving collected trends failed", "error", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to save trends: %v", err))
+			return result, nil
 		}
+	}
 
-		trends, err := collector.Collect(ctx, source)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("failed to collect from %s: %v", sourceID, err))
-			continue
+	for _, stage := range s.stages {
+		if err := stage.Process(ctx, profile, result); err != nil {
+			slog.WarnContext(ctx, "collection stage failed", "stage", stage.Name(), "error", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", stage.Name(), err))
 		}
+	}
 
-		result.Trends = append(result.Trends, trends...)
+	result.Duration = time.Since(started)
+	slog.InfoContext(ctx, "collection completed",
+		"items", len(result.Trends), "new", len(result.NewTrends), "errors", len(result.Errors), "duration", result.Duration)
+	if s.dispatcher != nil {
+		for _, t := range result.NewTrends {
+			s.dispatcher.Dispatch(&event.TrendCollectedEvent{
+				TrendID:   t.ID(),
+				SourceID:  t.SourceID(),
+				Timestamp: t.CollectedAt().UTC().Format(time.RFC3339),
+			})
+		}
+		s.dispatcher.Dispatch(&event.CollectionCompletedEvent{
+			JobID:      result.JobID,
+			Profile:    profile,
+			ItemsCount: len(result.Trends),
+			NewCount:   len(result.NewTrends),
+			Errors:     result.Errors,
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	return result, nil
+}
+
+// collectSource runs the source's collector. A source that cannot be
+// collected yields no trends and an error message for the result.
+func (s *CollectorService) collectSource(ctx context.Context, source *entity.Source, sourceID string) ([]*entity.Trend, string) {
+	ctx = logging.With(ctx, "source", sourceID)
+	if source == nil {
+		return nil, s.failed(ctx, sourceID, "config", fmt.Sprintf("source not found: %s", sourceID))
+	}
+	if !source.Enabled() {
+		slog.DebugContext(ctx, "source disabled, skipping")
+		return nil, ""
 	}
 
-	if len(result.Trends) > 0 {
-		if err := s.trendRepo.SaveBatch(ctx, result.Trends); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("failed to save trends: %v", err))
+	collector, exists := s.collectors[source.Type()]
+	if !exists {
+		return nil, s.failed(ctx, sourceID, "config", fmt.Sprintf("collector not found for type: %s", source.Type()))
+	}
+
+	ctx = logging.With(ctx, "source_type", source.Type())
+	started := time.Now()
+	trends, err := collector.Collect(ctx, source)
+	metrics.CollectionDuration.Since(started, sourceID)
+	if err != nil {
+		return nil, s.failed(ctx, sourceID, collectorErrorType(err), fmt.Sprintf("failed to collect from %s: %v", sourceID, err))
+	}
+	slog.InfoContext(ctx, "source collected", "items", len(trends), "duration", time.Since(started))
+
+	now := time.Now()
+	metrics.ItemsCollected.Add(float64(len(trends)), sourceID)
+	metrics.LastCollection.Set(float64(now.Unix()), sourceID)
+	s.mu.Lock()
+	st := s.statuses[sourceID]
+	st.LastSuccess = now
+	s.statuses[sourceID] = st
+	s.mu.Unlock()
+	return trends, ""
+}
+
+// failed records and logs a failed collection of sourceID and returns msg.
+func (s *CollectorService) failed(ctx context.Context, sourceID, errType, msg string) string {
+	slog.ErrorContext(ctx, "source collection failed", "error_type", errType, "error", msg)
+	metrics.CollectorErrors.Inc(sourceID, errType)
+	s.mu.Lock()
+	st := s.statuses[sourceID]
+	st.LastError, st.LastErrorAt = msg, time.Now()
+	s.statuses[sourceID] = st
+	s.mu.Unlock()
+	return msg
+}
+
+// collectorErrorType classifies collector errors for metrics.
+func collectorErrorType(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
 		}
+		return "network"
 	}
+	return "collector"
+}
 
-	return result, nil
+// SourceStatuses returns the status of every source collected since the
+// server started, by source ID.
+func (s *CollectorService) SourceStatuses() map[string]SourceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]SourceStatus, len(s.statuses))
+	for id, st := range s.statuses {
+		out[id] = st
+	}
+	return out
+}
+
+// carryOver copies state that collectors do not know about (stars, LLM
+// summaries, tags) from stored trends onto re-collected ones, and records
+// which trends are new.
+func (s *CollectorService) carryOver(ctx context.Context, result *entity.CollectionResult) {
+	stored, _, err := s.trendRepo.List(ctx, ListOptions{})
+	if err != nil {
+		result.NewTrends = result.Trends
+		return
+	}
+
+	existing := make(map[string]*entity.Trend, len(stored))
+	for _, t := range stored {
+		existing[t.ID()] = t
+	}
+
+	for _, t := range result.Trends {
+		old, ok := existing[t.ID()]
+		if !ok {
+			result.NewTrends = append(result.NewTrends, t)
+			continue
+		}
+
+		t.SetStarred(old.Starred())
+		if t.Category() == "" {
+			t.SetCategory(old.Category())
+		}
+		t.SetTags(mergeTags(old.Tags(), t.Tags()))
+		t.SetSummaries(old.Summaries())
+		for k, v := range old.Metadata() {
+			if _, set := t.Metadata()[k]; !set {
+				t.SetMetadata(k, v)
+			}
+		}
+	}
 }
 
 type TrendService struct {
-	repo TrendRepository
+	repo       TrendRepository
+	dispatcher event.EventDispatcher
+}
+
+type TrendOption func(*TrendService)
+
+// WithTrendDispatcher dispatches trend.starred events when trends are
+// starred or unstarred.
+func WithTrendDispatcher(d event.EventDispatcher) TrendOption {
+	return func(s *TrendService) {
+		s.dispatcher = d
+	}
 }
 
-func NewTrendService(repo TrendRepository) *TrendService {
-	return &TrendService{repo: repo}
+func NewTrendService(repo TrendRepository, opts ...TrendOption) *TrendService {
+	s := &TrendService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *TrendService) List(ctx context.Context, opts ListOptions) ([]*entity.Trend, int, error) {
@@ -129,22 +349,35 @@ func (s *TrendService) Search(ctx context.Context, query string, opts SearchOpti
 	return s.repo.Search(ctx, query, opts)
 }
 
-func (s *TrendService) Star(ctx context.Context, id string) error {
-	trend, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	trend.SetStarred(true)
+func (s *TrendService) Update(ctx context.Context, trend *entity.Trend) error {
 	return s.repo.Update(ctx, trend)
 }
 
+func (s *TrendService) Star(ctx context.Context, id string) error {
+	return s.setStarred(ctx, id, true)
+}
+
 func (s *TrendService) Unstar(ctx context.Context, id string) error {
+	return s.setStarred(ctx, id, false)
+}
+
+func (s *TrendService) setStarred(ctx context.Context, id string, starred bool) error {
 	trend, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	trend.SetStarred(false)
-	return s.repo.Update(ctx, trend)
+	trend.SetStarred(starred)
+	if err := s.repo.Update(ctx, trend); err != nil {
+		return err
+	}
+	if s.dispatcher != nil {
+		s.dispatcher.Dispatch(&event.TrendStarredEvent{
+			TrendID:   id,
+			Starred:   starred,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	return nil
 }
 
 func (s *TrendService) Delete(ctx context.Context, id string) error {
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"r3f-trends/internal/domain/entity"
+)
+
+type SuggestionRepository interface {
+	SaveSuggestions(ctx context.Context, profile string, suggestions []*entity.Suggestion) error
+	ListSuggestions(ctx context.Context, profile string) ([]*entity.Suggestion, error)
+	FindSuggestion(ctx context.Context, profile, id string) (*entity.Suggestion, error)
+}
+
+// DefaultSuggestionHistory is how many earlier suggestions are shown to the
+// suggester so it does not propose them again.
+const DefaultSuggestionHistory = 20
+
+// SuggestionService keeps the agent's blog post ideas and the author's
+// feedback on them.
+type SuggestionService struct {
+	repo     SuggestionRepository
+	profiles ProfileLoader
+	history  int
+}
+
+func NewSuggestionService(repo SuggestionRepository, profiles ProfileLoader, history int) *SuggestionService {
+	if history <= 0 {
+		history = DefaultSuggestionHistory
+	}
+	return &SuggestionService{repo: repo, profiles: profiles, history: history}
+}
+
+// List returns a profile's suggestions, newest first, optionally filtered by
+// status.
+func (s *SuggestionService) List(ctx context.Context, profile string, status entity.SuggestionStatus) ([]*entity.Suggestion, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return nil, err
+	}
+	all, err := s.repo.ListSuggestions(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.Suggestion, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if status == "" || all[i].Status() == status {
+			result = append(result, all[i])
+		}
+	}
+	return result, nil
+}
+
+func (s *SuggestionService) Get(ctx context.Context, profile, id string) (*entity.Suggestion, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return nil, err
+	}
+	return s.repo.FindSuggestion(ctx, profile, id)
+}
+
+func (s *SuggestionService) SetStatus(ctx context.Context, profile, id string, status entity.SuggestionStatus) (*entity.Suggestion, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return nil, err
+	}
+	suggestion, err := s.repo.FindSuggestion(ctx, profile, id)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion.SetStatus(status)
+	if err := s.repo.SaveSuggestions(ctx, profile, []*entity.Suggestion{suggestion}); err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// Recent returns the most recently proposed or reviewed suggestions, in any
+// status, for feeding back into the suggester prompt.
+func (s *SuggestionService) Recent(ctx context.Context, profile string) ([]*entity.Suggestion, error) {
+	all, err := s.repo.ListSuggestions(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	recent := append([]*entity.Suggestion(nil), all...)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].UpdatedAt().After(recent[j].UpdatedAt())
+	})
+	if len(recent) > s.history {
+		recent = recent[:s.history]
+	}
+	return recent, nil
+}
+
+// Record stores fresh suggestions and returns them. Ideas that were already
+// suggested for the profile, in any status, are dropped.
+func (s *SuggestionService) Record(ctx context.Context, profile string, suggestions []*entity.Suggestion) ([]*entity.Suggestion, error) {
+	existing, err := s.repo.ListSuggestions(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing)+len(suggestions))
+	for _, sug := range existing {
+		seen[sug.ID()] = true
+	}
+
+	fresh := make([]*entity.Suggestion, 0, len(suggestions))
+	for _, sug := range suggestions {
+		if seen[sug.ID()] {
+			continue
+		}
+		seen[sug.ID()] = true
+		fresh = append(fresh, sug)
+	}
+
+	if len(fresh) == 0 {
+		return fresh, nil
+	}
+	if err := s.repo.SaveSuggestions(ctx, profile, fresh); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
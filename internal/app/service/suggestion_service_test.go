@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/cache"
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestSuggestTopicsRemembersFeedback(t *testing.T) {
+	ctx := context.Background()
+	profiles := yaml.NewProfileLoader("../../../config/profiles")
+	suggestions := service.NewSuggestionService(markdown.NewSuggestionRepository(t.TempDir()), profiles, 0)
+
+	provider := fake.New()
+	svc := service.NewAgentService(
+		llm.NewAgent(provider),
+		newTrendService(t, entity.NewTrend("hn-5", "Zig 1.0 released", "")),
+		profiles,
+		"tech",
+		service.WithSuggestions(suggestions),
+	)
+
+	result, err := svc.SuggestTopics(ctx, "tech")
+	if err != nil {
+		t.Fatalf("SuggestTopics: %v", err)
+	}
+	first := result.Suggestions
+	if len(first) != 1 || first[0].Status() != entity.SuggestionNew {
+		t.Fatalf("suggestions = %+v", first)
+	}
+
+	if _, err := suggestions.SetStatus(ctx, "tech", first[0].ID(), entity.SuggestionRejected); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+
+	again, err := svc.SuggestTopics(ctx, "tech")
+	if err != nil {
+		t.Fatalf("SuggestTopics: %v", err)
+	}
+	if len(again.Suggestions) != 0 {
+		t.Errorf("repeated suggestion was returned again: %+v", again.Suggestions)
+	}
+
+	calls := provider.Calls()
+	prompt := calls[len(calls)-1].Messages[1].Content
+	if !strings.Contains(prompt, first[0].Title()+" (rejected)") {
+		t.Errorf("prompt does not list the rejected idea:\n%s", prompt)
+	}
+
+	stored, err := suggestions.List(ctx, "tech", entity.SuggestionRejected)
+	if err != nil || len(stored) != 1 {
+		t.Errorf("List(rejected) = %d, %v", len(stored), err)
+	}
+}
+
+func TestSuggestTopicsBypassesCache(t *testing.T) {
+	ctx := context.Background()
+	profiles := yaml.NewProfileLoader("../../../config/profiles")
+	suggestions := service.NewSuggestionService(markdown.NewSuggestionRepository(t.TempDir()), profiles, 0)
+
+	provider := fake.New()
+	cached, err := cache.New(llm.NewAgent(provider), t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.New: %v", err)
+	}
+	svc := service.NewAgentService(
+		cached,
+		newTrendService(t, entity.NewTrend("hn-5", "Zig 1.0 released", "")),
+		profiles,
+		"tech",
+		service.WithSuggestions(suggestions),
+	)
+
+	first, err := svc.SuggestTopics(ctx, "tech")
+	if err != nil || len(first.Suggestions) != 1 {
+		t.Fatalf("SuggestTopics = %+v, %v", first, err)
+	}
+	// The third prompt matches the second, since nothing new was stored.
+	for range 2 {
+		if _, err := svc.SuggestTopics(ctx, "tech"); err != nil {
+			t.Fatalf("SuggestTopics: %v", err)
+		}
+	}
+
+	calls := provider.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("got %d model calls, want every request to bypass the cache", len(calls))
+	}
+	prompt := calls[1].Messages[1].Content
+	if !strings.Contains(prompt, first.Suggestions[0].Title()+" (new)") {
+		t.Errorf("prompt does not list the pending idea:\n%s", prompt)
+	}
+}
+
+func TestSuggestionsRejectUnknownProfiles(t *testing.T) {
+	ctx := context.Background()
+	suggestions := service.NewSuggestionService(markdown.NewSuggestionRepository(t.TempDir()),
+		yaml.NewProfileLoader("../../../config/profiles"), 0)
+
+	for _, profile := range []string{"nope", "../config", `..\tech`} {
+		if _, err := suggestions.List(ctx, profile, ""); !errors.Is(err, domain.ErrProfileNotFound) {
+			t.Errorf("List(%q) = %v, want ErrProfileNotFound", profile, err)
+		}
+		if _, err := suggestions.SetStatus(ctx, profile, "x", entity.SuggestionAccepted); !errors.Is(err, domain.ErrProfileNotFound) {
+			t.Errorf("SetStatus(%q) = %v, want ErrProfileNotFound", profile, err)
+		}
+	}
+}
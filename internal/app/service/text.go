@@ -0,0 +1,106 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that say nothing about what a title
+// is about.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "an": true, "and": true,
+	"are": true, "as": true, "at": true, "be": true, "been": true, "but": true,
+	"by": true, "can": true, "do": true, "does": true, "for": true, "from": true,
+	"has": true, "have": true, "how": true, "i": true, "if": true, "in": true,
+	"into": true, "is": true, "it": true, "its": true, "just": true, "more": true,
+	"my": true, "new": true, "no": true, "not": true, "now": true, "of": true,
+	"on": true, "one": true, "or": true, "our": true, "out": true, "over": true,
+	"s": true, "show": true, "so": true, "than": true, "that": true, "the": true,
+	"their": true, "there": true, "this": true, "to": true, "up": true, "us": true,
+	"use": true, "using": true, "via": true, "vs": true, "was": true, "we": true,
+	"what": true, "when": true, "which": true, "who": true, "why": true, "will": true,
+	"with": true, "you": true, "your": true,
+}
+
+// IsStopWord reports whether w, in lower case, is one of the stop words
+// that text matching across the app ignores.
+func IsStopWord(w string) bool {
+	return stopWords[w]
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// terms returns the lowercased words of s that carry meaning: no stop
+// words and no single characters.
+func terms(s string) []string {
+	var out []string
+	for _, w := range splitWords(s) {
+		if len(w) > 1 && !stopWords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// phrases splits s into runs of meaningful words, breaking at punctuation
+// and stop words, so that "the rise of Rust: a new compiler" gives
+// [rise] [rust] [compiler].
+func phrases(s string) [][]string {
+	var out [][]string
+	var run []string
+	flush := func() {
+		if len(run) > 0 {
+			out = append(out, run)
+			run = nil
+		}
+	}
+
+	for _, field := range strings.Fields(strings.ToLower(s)) {
+		// Punctuation inside a field, as in "c++" or "node.js", stays part
+		// of the word; at either end it ends the phrase.
+		trimmed := strings.TrimRightFunc(field, isPunct)
+		word := strings.TrimLeftFunc(trimmed, isPunct)
+		if len(word) != len(trimmed) {
+			flush()
+		}
+		for _, w := range splitWords(word) {
+			if len(w) < 2 || stopWords[w] || isNumber(w) {
+				flush()
+				continue
+			}
+			run = append(run, w)
+		}
+		if len(trimmed) != len(field) {
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func isNumber(w string) bool {
+	return strings.IndexFunc(w, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
+
+// matchPhrases returns the keywords found in text as whole words or word
+// sequences, ignoring case and punctuation.
+func matchPhrases(text string, keywords []string) []string {
+	padded := " " + strings.Join(splitWords(text), " ") + " "
+
+	var matched []string
+	for _, k := range keywords {
+		phrase := strings.Join(splitWords(k), " ")
+		if phrase != "" && strings.Contains(padded, " "+phrase+" ") {
+			matched = append(matched, k)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"r3f-trends/internal/domain"
+)
+
+// UsageEvent is the token count of a single LLM call as reported by the
+// provider.
+type UsageEvent struct {
+	Provider         string
+	Model            string
+	Operation        string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// UsageRecorder is notified after every LLM call that reached a provider.
+// Responses served from a cache are not reported.
+type UsageRecorder interface {
+	Record(ctx context.Context, ev UsageEvent)
+}
+
+// UsageRecord aggregates calls for one day, profile, operation and model.
+type UsageRecord struct {
+	Date             string  `json:"date"`
+	Profile          string  `json:"profile"`
+	Operation        string  `json:"operation"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+func (r UsageRecord) SameBucket(o UsageRecord) bool {
+	return r.Date == o.Date && r.Profile == o.Profile && r.Operation == o.Operation &&
+		r.Provider == o.Provider && r.Model == o.Model
+}
+
+func (r *UsageRecord) Add(o UsageRecord) {
+	r.Calls += o.Calls
+	r.PromptTokens += o.PromptTokens
+	r.CompletionTokens += o.CompletionTokens
+	r.Cost += o.Cost
+}
+
+type UsageRepository interface {
+	AddUsage(ctx context.Context, rec UsageRecord) error
+	ListUsage(ctx context.Context, from, to string) ([]UsageRecord, error)
+}
+
+// ModelPrice is the cost per million prompt (input) and completion (output)
+// tokens.
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+const (
+	// BudgetRefuse makes AgentService fail requests once the daily budget
+	// is spent.
+	BudgetRefuse = "refuse"
+	// BudgetDegrade keeps serving requests with fewer, smaller LLM calls and
+	// pauses background enrichment.
+	BudgetDegrade = "degrade"
+)
+
+type UsageReport struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Records []UsageRecord `json:"records"`
+	Total   UsageRecord   `json:"total"`
+	Budget  *BudgetStatus `json:"budget,omitempty"`
+}
+
+type BudgetStatus struct {
+	Daily    float64 `json:"daily"`
+	Spent    float64 `json:"spent"`
+	Mode     string  `json:"mode"`
+	Exceeded bool    `json:"exceeded"`
+}
+
+type UsageService struct {
+	repo        UsageRepository
+	prices      map[string]ModelPrice
+	dailyBudget float64
+	budgetMode  string
+	now         func() time.Time
+}
+
+type UsageOption func(*UsageService)
+
+// WithPrices sets per-model prices, keyed by "provider/model" or "model".
+func WithPrices(prices map[string]ModelPrice) UsageOption {
+	return func(s *UsageService) {
+		s.prices = prices
+	}
+}
+
+// WithDailyBudget limits the cost spent per day; mode is BudgetRefuse or
+// BudgetDegrade.
+func WithDailyBudget(limit float64, mode string) UsageOption {
+	return func(s *UsageService) {
+		s.dailyBudget = limit
+		s.budgetMode = mode
+	}
+}
+
+func WithUsageClock(now func() time.Time) UsageOption {
+	return func(s *UsageService) {
+		s.now = now
+	}
+}
+
+func NewUsageService(repo UsageRepository, opts ...UsageOption) (*UsageService, error) {
+	s := &UsageService{
+		repo:       repo,
+		budgetMode: BudgetRefuse,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	switch s.budgetMode {
+	case BudgetRefuse, BudgetDegrade:
+	default:
+		return nil, fmt.Errorf("%w: unknown budget mode %q", domain.ErrInvalidConfig, s.budgetMode)
+	}
+	return s, nil
+}
+
+func (s *UsageService) Record(ctx context.Context, ev UsageEvent) {
+	rec := UsageRecord{
+		Date:             s.today(),
+		Profile:          ProfileFromContext(ctx),
+		Operation:        ev.Operation,
+		Provider:         ev.Provider,
+		Model:            ev.Model,
+		Calls:            1,
+		PromptTokens:     ev.PromptTokens,
+		CompletionTokens: ev.CompletionTokens,
+		Cost:             s.cost(ev),
+	}
+
+	if err := s.repo.AddUsage(ctx, rec); err != nil {
+		slog.ErrorContext(ctx, "recording llm usage failed", "error", err)
+	}
+}
+
+// Report aggregates usage between from and to (YYYY-MM-DD, inclusive).
+// Empty bounds default to today.
+func (s *UsageService) Report(ctx context.Context, from, to string) (*UsageReport, error) {
+	if from == "" {
+		from = s.today()
+	}
+	if to == "" {
+		to = s.today()
+	}
+
+	records, err := s.repo.ListUsage(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{From: from, To: to, Records: records}
+	if report.Records == nil {
+		report.Records = []UsageRecord{}
+	}
+	for _, r := range records {
+		report.Total.Add(r)
+	}
+
+	if s.dailyBudget > 0 {
+		status, err := s.Budget(ctx)
+		if err != nil {
+			return nil, err
+		}
+		report.Budget = status
+	}
+
+	return report, nil
+}
+
+func (s *UsageService) Budget(ctx context.Context) (*BudgetStatus, error) {
+	records, err := s.repo.ListUsage(ctx, s.today(), s.today())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &BudgetStatus{Daily: s.dailyBudget, Mode: s.budgetMode}
+	for _, r := range records {
+		status.Spent += r.Cost
+	}
+	status.Exceeded = s.dailyBudget > 0 && status.Spent >= s.dailyBudget
+	return status, nil
+}
+
+// CheckBudget returns domain.ErrBudgetExceeded when today's budget is spent
+// and the mode is BudgetRefuse. degraded reports BudgetDegrade instead.
+func (s *UsageService) CheckBudget(ctx context.Context) (degraded bool, err error) {
+	if s.dailyBudget <= 0 {
+		return false, nil
+	}
+
+	status, err := s.Budget(ctx)
+	if err != nil || !status.Exceeded {
+		return false, err
+	}
+	if s.budgetMode == BudgetDegrade {
+		return true, nil
+	}
+	return false, fmt.Errorf("%w: spent %.4f of %.4f today", domain.ErrBudgetExceeded, status.Spent, status.Daily)
+}
+
+func (s *UsageService) cost(ev UsageEvent) float64 {
+	price, ok := s.prices[ev.Provider+"/"+ev.Model]
+	if !ok {
+		price = s.prices[ev.Model]
+	}
+	return (float64(ev.PromptTokens)*price.Input + float64(ev.CompletionTokens)*price.Output) / 1e6
+}
+
+func (s *UsageService) today() string {
+	return s.now().Format("2006-01-02")
+}
+
+type profileKey struct{}
+
+// ContextWithProfile records which profile an LLM call is made for, so usage
+// can be attributed to it.
+func ContextWithProfile(ctx context.Context, profile string) context.Context {
+	return context.WithValue(ctx, profileKey{}, profile)
+}
+
+func ProfileFromContext(ctx context.Context) string {
+	profile, _ := ctx.Value(profileKey{}).(string)
+	return profile
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/agent/fake"
+	"r3f-trends/internal/adapter/driven/agent/llm"
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+)
+
+func TestUsageIsRecordedAndBudgetEnforced(t *testing.T) {
+	ctx := context.Background()
+	usage, err := service.NewUsageService(markdown.NewUsageRepository(t.TempDir()),
+		service.WithPrices(map[string]service.ModelPrice{"fake": {Input: 1e6, Output: 1e6}}),
+		service.WithDailyBudget(10, service.BudgetRefuse),
+	)
+	if err != nil {
+		t.Fatalf("NewUsageService: %v", err)
+	}
+
+	svc := service.NewAgentService(
+		llm.NewAgent(fake.New(), llm.WithUsageRecorder(usage)),
+		newTrendService(t, entity.NewTrend("hn-4", "SQLite gets a new query planner", "")),
+		yaml.NewProfileLoader("../../../config/profiles"),
+		"tech",
+		service.WithUsage(usage),
+	)
+
+	if _, err := svc.Summarize(ctx, "hn-4", false); err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+
+	report, err := usage.Report(ctx, "", "")
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(report.Records) != 1 {
+		t.Fatalf("records = %+v", report.Records)
+	}
+	r := report.Records[0]
+	if r.Profile != "tech" || r.Operation != llm.OperationSummarize || r.Calls != 1 || r.PromptTokens == 0 {
+		t.Errorf("record = %+v", r)
+	}
+	if want := float64(r.PromptTokens + r.CompletionTokens); r.Cost != want {
+		t.Errorf("cost = %v, want %v", r.Cost, want)
+	}
+	if report.Budget == nil || !report.Budget.Exceeded {
+		t.Fatalf("budget = %+v, want exceeded", report.Budget)
+	}
+
+	if _, err := svc.Summarize(ctx, "hn-4", true); !errors.Is(err, domain.ErrBudgetExceeded) {
+		t.Errorf("err = %v, want ErrBudgetExceeded", err)
+	}
+}
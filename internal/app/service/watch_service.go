@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/event"
+)
+
+type AlertRepository interface {
+	// SaveAlerts adds new alerts and replaces existing ones with the same ID.
+	SaveAlerts(ctx context.Context, profile string, alerts []*entity.Alert) error
+	ListAlerts(ctx context.Context, profile string) ([]*entity.Alert, error)
+}
+
+// WatchService checks new trends against the watch rules of the profile
+// they were collected for. Matching trends are tagged with the rule and an
+// alert is recorded and dispatched. It runs as a collection stage.
+type WatchService struct {
+	profiles   ProfileLoader
+	trendSvc   *TrendService
+	repo       AlertRepository
+	dispatcher event.EventDispatcher
+	now        func() time.Time
+}
+
+type WatchOption func(*WatchService)
+
+// WithAlertDispatcher dispatches an AlertRaisedEvent for each new alert.
+func WithAlertDispatcher(d event.EventDispatcher) WatchOption {
+	return func(s *WatchService) {
+		s.dispatcher = d
+	}
+}
+
+func NewWatchService(profiles ProfileLoader, trendSvc *TrendService, repo AlertRepository, opts ...WatchOption) *WatchService {
+	s := &WatchService{
+		profiles: profiles,
+		trendSvc: trendSvc,
+		repo:     repo,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *WatchService) Name() string {
+	return "watch"
+}
+
+func (s *WatchService) Process(ctx context.Context, profile string, result *entity.CollectionResult) error {
+	_, err := s.Watch(ctx, profile, result.NewTrends)
+	return err
+}
+
+// Watch checks trends against the profile's rules and returns the alerts
+// raised. A rule matching a trend it already alerted on raises nothing.
+func (s *WatchService) Watch(ctx context.Context, profile string, trends []*entity.Trend) ([]*entity.Alert, error) {
+	p, err := s.profiles.Load(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	rules := p.Watch()
+	if len(rules) == 0 || len(trends) == 0 {
+		return nil, nil
+	}
+
+	// The loader has validated the expressions already.
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		if rule.Regex != "" {
+			if patterns[i], err = regexp.Compile(rule.Regex); err != nil {
+				return nil, fmt.Errorf("watch rule %s: %w", rule.Name, err)
+			}
+		}
+	}
+
+	existing, err := s.repo.ListAlerts(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, a := range existing {
+		seen[a.ID] = true
+	}
+
+	var alerts []*entity.Alert
+	for _, t := range trends {
+		var tags []string
+		for i, rule := range rules {
+			match, ok := matchRule(t, rule, patterns[i])
+			if !ok {
+				continue
+			}
+			tags = append(tags, entity.WatchTag(rule.Name))
+
+			id := entity.AlertID(profile, rule.Name, t.ID())
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			alerts = append(alerts, &entity.Alert{
+				ID:        id,
+				Profile:   profile,
+				Rule:      rule.Name,
+				TrendID:   t.ID(),
+				Title:     t.Title(),
+				URL:       t.URL(),
+				Source:    t.Source(),
+				Score:     t.Score(),
+				Match:     match,
+				CreatedAt: s.now().UTC(),
+			})
+		}
+
+		if merged := mergeTags(t.Tags(), tags); len(merged) != len(t.Tags()) {
+			t.SetTags(merged)
+			if err := s.trendSvc.Update(ctx, t); err != nil {
+				return nil, fmt.Errorf("%s: %w", t.ID(), err)
+			}
+		}
+	}
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+	if err := s.repo.SaveAlerts(ctx, profile, alerts); err != nil {
+		return nil, err
+	}
+
+	if s.dispatcher != nil {
+		for _, a := range alerts {
+			s.dispatcher.Dispatch(&event.AlertRaisedEvent{
+				AlertID:   a.ID,
+				Profile:   a.Profile,
+				Rule:      a.Rule,
+				TrendID:   a.TrendID,
+				Title:     a.Title,
+				URL:       a.URL,
+				Timestamp: a.CreatedAt.Format(time.RFC3339),
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// Alerts returns a profile's alerts, newest first, optionally only those
+// not seen yet.
+func (s *WatchService) Alerts(ctx context.Context, profile string, unseenOnly bool) ([]*entity.Alert, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return nil, err
+	}
+	all, err := s.repo.ListAlerts(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*entity.Alert, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if !unseenOnly || !all[i].Seen {
+			alerts = append(alerts, all[i])
+		}
+	}
+	return alerts, nil
+}
+
+// MarkSeen marks the alerts with the given IDs as seen, or all of them if
+// ids is empty, and returns how many changed.
+func (s *WatchService) MarkSeen(ctx context.Context, profile string, ids []string) (int, error) {
+	if _, err := s.profiles.Load(ctx, profile); err != nil {
+		return 0, err
+	}
+	all, err := s.repo.ListAlerts(ctx, profile)
+	if err != nil {
+		return 0, err
+	}
+
+	var changed []*entity.Alert
+	for _, a := range all {
+		if a.Seen || (len(ids) > 0 && !slices.Contains(ids, a.ID)) {
+			continue
+		}
+		a.Seen = true
+		changed = append(changed, a)
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+	return len(changed), s.repo.SaveAlerts(ctx, profile, changed)
+}
+
+// matchRule reports whether t satisfies rule and describes what matched.
+func matchRule(t *entity.Trend, rule entity.WatchRule, pattern *regexp.Regexp) (string, bool) {
+	if t.Score() < rule.MinScore {
+		return "", false
+	}
+	if len(rule.Sources) > 0 && !slices.Contains(rule.Sources, t.SourceID()) {
+		return "", false
+	}
+
+	text := t.Title()
+	if t.Summary() != "" {
+		text += "\n" + t.Summary()
+	}
+	if latest := t.LatestSummary(); latest != nil {
+		text += "\n" + latest.Text
+	}
+
+	if matched := matchPhrases(text, rule.Keywords); len(matched) > 0 {
+		return strings.Join(matched, ", "), true
+	}
+	if pattern != nil {
+		if m := pattern.FindString(text); m != "" {
+			return m, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"r3f-trends/internal/adapter/driven/config/yaml"
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/event"
+)
+
+type recordingDispatcher struct {
+	events []event.Event
+}
+
+func (d *recordingDispatcher) Subscribe(string, event.EventHandler) {}
+func (d *recordingDispatcher) Dispatch(e event.Event)               { d.events = append(d.events, e) }
+
+func TestWatchRaisesAlertsOnce(t *testing.T) {
+	ctx := context.Background()
+
+	release := entity.NewTrend("hn-1", "Go 1.26 is released", "https://go.dev/blog/go1.26")
+	rust := entity.NewTrend("hn-2", "Rust in the Linux kernel", "")
+	rust.SetSourceID("hackernews-newest")
+	rust.SetScore(500)
+	trendSvc := newTrendService(t, release, rust)
+
+	dispatcher := &recordingDispatcher{}
+	svc := service.NewWatchService(
+		yaml.NewProfileLoader("../../../config/profiles"),
+		trendSvc,
+		markdown.NewAlertRepository(t.TempDir()),
+		service.WithAlertDispatcher(dispatcher),
+	)
+
+	alerts, err := svc.Watch(ctx, "tech", []*entity.Trend{release, rust})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Rule != "go-release" || alerts[0].Match != "Go 1.26" {
+		t.Fatalf("alerts = %+v, want one go-release alert", alerts)
+	}
+	if len(dispatcher.events) != 1 || dispatcher.events[0].Type() != "alert.raised" {
+		t.Errorf("events = %v", dispatcher.events)
+	}
+
+	stored, err := trendSvc.Get(ctx, "hn-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !slices.Contains(stored.Tags(), "watch:go-release") {
+		t.Errorf("tags = %v", stored.Tags())
+	}
+
+	if again, _ := svc.Watch(ctx, "tech", []*entity.Trend{stored}); len(again) != 0 {
+		t.Errorf("alerted again: %+v", again)
+	}
+
+	if marked, err := svc.MarkSeen(ctx, "tech", nil); err != nil || marked != 1 {
+		t.Errorf("MarkSeen = %d, %v", marked, err)
+	}
+	if unseen, _ := svc.Alerts(ctx, "tech", true); len(unseen) != 0 {
+		t.Errorf("unseen = %+v", unseen)
+	}
+}
+
+func TestAlertsRejectUnknownProfiles(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewWatchService(
+		yaml.NewProfileLoader("../../../config/profiles"),
+		newTrendService(t),
+		markdown.NewAlertRepository(t.TempDir()),
+	)
+
+	for _, profile := range []string{"nope", "../config"} {
+		if _, err := svc.Alerts(ctx, profile, false); !errors.Is(err, domain.ErrProfileNotFound) {
+			t.Errorf("Alerts(%q) = %v, want ErrProfileNotFound", profile, err)
+		}
+		if _, err := svc.MarkSeen(ctx, profile, nil); !errors.Is(err, domain.ErrProfileNotFound) {
+			t.Errorf("MarkSeen(%q) = %v, want ErrProfileNotFound", profile, err)
+		}
+	}
+}
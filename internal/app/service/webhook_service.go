@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"r3f-trends/internal/domain"
+	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/event"
+)
+
+const (
+	// DefaultWebhookAttempts is how often a notification is tried before it
+	// goes to the dead-letter log.
+	DefaultWebhookAttempts = 4
+	// DefaultWebhookBackoff is the wait before the first retry; it doubles
+	// with each further retry.
+	DefaultWebhookBackoff = 2 * time.Second
+)
+
+// Webhook sink formats.
+const (
+	WebhookJSON    = "json"
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+	WebhookNtfy    = "ntfy"
+)
+
+// WebhookSink is an endpoint that is notified of the listed events. With a
+// secret, requests are signed with HMAC-SHA256.
+type WebhookSink struct {
+	Name   string
+	URL    string
+	Format string
+	Secret string
+	Events []string
+}
+
+// WebhookSender delivers one notification to a sink in the sink's format.
+type WebhookSender interface {
+	Send(ctx context.Context, sink WebhookSink, n entity.Notification) error
+}
+
+type DeadLetterRepository interface {
+	SaveDeadLetter(ctx context.Context, letter *entity.DeadLetter) error
+	ListDeadLetters(ctx context.Context) ([]*entity.DeadLetter, error)
+}
+
+// WebhookStatusError is returned by senders when a sink answers with an
+// error status. Client errors other than 408 and 429 are not retried.
+type WebhookStatusError struct {
+	StatusCode int
+}
+
+func (e *WebhookStatusError) Error() string {
+	return fmt.Sprintf("webhook returned HTTP %d", e.StatusCode)
+}
+
+func (e *WebhookStatusError) retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests
+}
+
+type WebhookConfig struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// WebhookResult is the outcome of sending to one sink.
+type WebhookResult struct {
+	Sink  string `json:"sink"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookService sends domain events to webhook sinks, retrying with
+// exponential backoff and recording what could not be delivered.
+type WebhookService struct {
+	sinks       []WebhookSink
+	sender      WebhookSender
+	deadLetters DeadLetterRepository
+	cfg         WebhookConfig
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+func NewWebhookService(sinks []WebhookSink, sender WebhookSender, deadLetters DeadLetterRepository, cfg WebhookConfig) *WebhookService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultWebhookAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultWebhookBackoff
+	}
+	return &WebhookService{
+		sinks:       sinks,
+		sender:      sender,
+		deadLetters: deadLetters,
+		cfg:         cfg,
+		sleep:       sleepContext,
+	}
+}
+
+// Subscribe registers the service for every event type a sink listens to.
+func (s *WebhookService) Subscribe(d event.EventDispatcher) {
+	var types []string
+	for _, sink := range s.sinks {
+		for _, t := range sink.Events {
+			if !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+	for _, t := range types {
+		d.Subscribe(t, func(e event.Event) {
+			s.Notify(context.Background(), e)
+		})
+	}
+}
+
+// Notify sends e to every sink subscribed to its type.
+func (s *WebhookService) Notify(ctx context.Context, e event.Event) {
+	n := NotificationFor(e)
+	for _, sink := range s.sinks {
+		if slices.Contains(sink.Events, e.Type()) {
+			s.deliver(ctx, sink, n)
+		}
+	}
+}
+
+// Test sends a sample notification to the named sink, or to all sinks if
+// name is empty, once and without retries.
+func (s *WebhookService) Test(ctx context.Context, name string) ([]WebhookResult, error) {
+	n := entity.Notification{
+		Event:     "webhook.test",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Title:     "Test notification",
+		Text:      "Webhooks are set up correctly.",
+	}
+
+	var results []WebhookResult
+	for _, sink := range s.sinks {
+		if name != "" && sink.Name != name {
+			continue
+		}
+		result := WebhookResult{Sink: sink.Name, OK: true}
+		if err := s.sender.Send(ctx, sink, n); err != nil {
+			result.OK, result.Error = false, err.Error()
+		}
+		results = append(results, result)
+	}
+	if name != "" && len(results) == 0 {
+		return nil, fmt.Errorf("webhook %s: %w", name, domain.ErrNotFound)
+	}
+	return results, nil
+}
+
+func (s *WebhookService) DeadLetters(ctx context.Context) ([]*entity.DeadLetter, error) {
+	return s.deadLetters.ListDeadLetters(ctx)
+}
+
+func (s *WebhookService) deliver(ctx context.Context, sink WebhookSink, n entity.Notification) {
+	var err error
+	attempt := 0
+	for attempt < s.cfg.MaxAttempts {
+		if attempt > 0 {
+			if s.sleep(ctx, s.cfg.Backoff<<(attempt-1)) != nil {
+				break
+			}
+		}
+		attempt++
+
+		if err = s.sender.Send(ctx, sink, n); err == nil {
+			return
+		}
+		var status *WebhookStatusError
+		if errors.As(err, &status) && !status.retryable() {
+			break
+		}
+	}
+
+	slog.ErrorContext(ctx, "webhook delivery failed, giving up",
+		"webhook", sink.Name, "event", n.Event, "attempts", attempt, "error", err)
+	letter := &entity.DeadLetter{
+		Sink:         sink.Name,
+		Notification: n,
+		Error:        err.Error(),
+		Attempts:     attempt,
+		FailedAt:     time.Now().UTC(),
+	}
+	if err := s.deadLetters.SaveDeadLetter(ctx, letter); err != nil {
+		slog.ErrorContext(ctx, "recording webhook dead letter failed", "webhook", sink.Name, "error", err)
+	}
+}
+
+// NotificationFor describes e for people reading it in a chat channel.
+func NotificationFor(e event.Event) entity.Notification {
+	n := entity.Notification{
+		Event:     e.Type(),
+		Timestamp: e.EventTimestamp(),
+		Title:     e.Type(),
+		Data:      e,
+	}
+
+	switch e := e.(type) {
+	case *event.CollectionCompletedEvent:
+		n.Title = fmt.Sprintf("Collected %d trends", e.ItemsCount)
+		n.Text = fmt.Sprintf("%d new trends for profile %s.", e.NewCount, e.Profile)
+		if len(e.Errors) > 0 {
+			n.Text += fmt.Sprintf(" %d errors, first: %s", len(e.Errors), e.Errors[0])
+		}
+	case *event.CollectionStartedEvent:
+		n.Title = "Collection started"
+		n.Text = fmt.Sprintf("Collecting for profile %s.", e.Profile)
+	case *event.AlertRaisedEvent:
+		n.Title = "Watch alert: " + e.Rule
+		n.Text = e.Title
+		n.URL = e.URL
+	}
+	return n
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
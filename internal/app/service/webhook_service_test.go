@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"r3f-trends/internal/adapter/driven/storage/markdown"
+	"r3f-trends/internal/app/service"
+	"r3f-trends/internal/domain/entity"
+	"r3f-trends/internal/domain/event"
+)
+
+type stubSender struct {
+	status int
+	calls  int
+}
+
+func (s *stubSender) Send(ctx context.Context, sink service.WebhookSink, n entity.Notification) error {
+	s.calls++
+	if s.status != 0 {
+		return &service.WebhookStatusError{StatusCode: s.status}
+	}
+	return nil
+}
+
+func TestWebhookRetriesThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	sinks := []service.WebhookSink{{Name: "hook", URL: "http://example.invalid", Events: []string{"alert.raised"}}}
+	alert := &event.AlertRaisedEvent{Rule: "go-release", Title: "Go 1.26 is released"}
+
+	for _, tc := range []struct {
+		status, attempts int
+	}{
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusNotFound, 1},
+	} {
+		sender := &stubSender{status: tc.status}
+		deadLetters := markdown.NewDeadLetterRepository(t.TempDir())
+		svc := service.NewWebhookService(sinks, sender, deadLetters, service.WebhookConfig{MaxAttempts: 3, Backoff: time.Millisecond})
+
+		svc.Notify(ctx, alert)
+		svc.Notify(ctx, &event.CollectionStartedEvent{})
+
+		letters, err := svc.DeadLetters(ctx)
+		if err != nil {
+			t.Fatalf("DeadLetters: %v", err)
+		}
+		if sender.calls != tc.attempts || len(letters) != 1 || letters[0].Attempts != tc.attempts {
+			t.Errorf("HTTP %d: %d calls, dead letters %+v", tc.status, sender.calls, letters)
+			continue
+		}
+		if n := letters[0].Notification; n.Title != "Watch alert: go-release" || n.Text != alert.Title {
+			t.Errorf("notification = %+v", n)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"time"
+)
+
+// WatchRule flags trends about terms the author follows closely. A trend
+// matches if it mentions one of the keywords or matches the regex, comes
+// from one of the sources (any if empty) and scores at least MinScore.
+type WatchRule struct {
+	Name string `yaml:"name" json:"name"`
+	// Keywords match whole words or word sequences, ignoring case and
+	// punctuation, so "Go 1.26" matches "go 1.26 is out".
+	Keywords []string `yaml:"keywords" json:"keywords,omitempty"`
+	Regex    string   `yaml:"regex" json:"regex,omitempty"`
+	Sources  []string `yaml:"sources" json:"sources,omitempty"`
+	MinScore int      `yaml:"min_score" json:"min_score,omitempty"`
+}
+
+// WatchTag is the tag added to trends matched by the rule called name.
+func WatchTag(name string) string {
+	return "watch:" + name
+}
+
+// Alert records that a watch rule matched a trend. Seen is set once the
+// author has looked at it.
+type Alert struct {
+	ID        string    `json:"id"`
+	Profile   string    `json:"profile"`
+	Rule      string    `json:"rule"`
+	TrendID   string    `json:"trend_id"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Score     int       `json:"score"`
+	Match     string    `json:"match"`
+	CreatedAt time.Time `json:"created_at"`
+	Seen      bool      `json:"seen"`
+}
+
+// AlertID derives the ID from the profile, rule and trend, so a trend
+// collected again does not raise the same alert twice.
+func AlertID(profile, rule, trendID string) string {
+	sum := sha1.Sum([]byte(profile + "\x00" + rule + "\x00" + trendID))
+	return hex.EncodeToString(sum[:6])
+}
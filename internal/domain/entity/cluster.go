@@ -0,0 +1,21 @@
+package entity
+
+// Cluster groups the trends of one day that cover the same story. A
+// cluster that continues a storyline from an earlier day keeps its ID and
+// FirstSeen date.
+type Cluster struct {
+	ID        string   `json:"id"`
+	Date      string   `json:"date"`
+	Name      string   `json:"name"`
+	NamedBy   string   `json:"named_by"`
+	Keywords  []string `json:"keywords"`
+	TrendIDs  []string `json:"trend_ids"`
+	FirstSeen string   `json:"first_seen"`
+}
+
+// How a cluster got its name.
+const (
+	ClusterNamedByLLM      = "llm"
+	ClusterNamedByKeywords = "keywords"
+	ClusterNamedByTitle    = "title"
+)
@@ -65,10 +65,12 @@ func (j *CollectionJob) AddError(err string) {
 }
 
 type CollectionResult struct {
-	JobID    string
-	Trends   []*Trend
-	Errors   []string
-	Duration time.Duration
+	JobID  string
+	Trends []*Trend
+	// NewTrends are the collected trends that were not stored before.
+	NewTrends []*Trend
+	Errors    []string
+	Duration  time.Duration
 }
 
 func (j *CollectionJob) ToDTO() *CollectionJobDTO {
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Digest is a newsletter-style summary of the top trends of a period,
+// rendered as Markdown and HTML.
+type Digest struct {
+	ID        string    `json:"id"`
+	Profile   string    `json:"profile"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	TrendIDs  []string  `json:"trend_ids"`
+	Markdown  string    `json:"markdown"`
+	HTML      string    `json:"html"`
+	CreatedAt time.Time `json:"created_at"`
+	// SentTo lists the recipients if the digest was emailed.
+	SentTo   []string `json:"sent_to,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	HTMLPath string   `json:"html_path,omitempty"`
+}
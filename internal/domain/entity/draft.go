@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Outline is the structure of a blog post before it is written.
+type Outline struct {
+	Title    string           `json:"title" yaml:"title"`
+	Sections []OutlineSection `json:"sections" yaml:"sections"`
+}
+
+type OutlineSection struct {
+	Heading string   `json:"heading" yaml:"heading"`
+	Points  []string `json:"points" yaml:"points"`
+	// Sources are the IDs of the trends the section draws on.
+	Sources []string `json:"sources" yaml:"sources"`
+}
+
+// DraftSource is a trend cited in a draft as [Ref].
+type DraftSource struct {
+	Ref     int    `json:"ref" yaml:"ref"`
+	TrendID string `json:"trend_id" yaml:"trend_id"`
+	Title   string `json:"title" yaml:"title"`
+	URL     string `json:"url" yaml:"url"`
+}
+
+// DraftSlug turns a post title into a file-name friendly slug.
+func DraftSlug(title string) string {
+	slug := strings.ReplaceAll(NormalizeTitle(title), " ", "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		return "draft"
+	}
+	return slug
+}
+
+// Draft is a generated blog post with the outline and sources it was
+// written from.
+type Draft struct {
+	ID           string        `json:"id"`
+	Profile      string        `json:"profile"`
+	Title        string        `json:"title"`
+	SuggestionID string        `json:"suggestion_id,omitempty"`
+	Outline      Outline       `json:"outline"`
+	Markdown     string        `json:"markdown"`
+	Sources      []DraftSource `json:"sources"`
+	Provider     string        `json:"provider"`
+	Model        string        `json:"model"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Path         string        `json:"path,omitempty"`
+}
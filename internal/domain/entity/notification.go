@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Notification is what is sent to webhook sinks about a domain event. Title
+// and Text are for chat-style sinks; Data is the event itself.
+type Notification struct {
+	Event     string `json:"event"`
+	Timestamp string `json:"timestamp"`
+	Title     string `json:"title"`
+	Text      string `json:"text"`
+	URL       string `json:"url,omitempty"`
+	Data      any    `json:"data,omitempty"`
+}
+
+// DeadLetter is a notification that a sink did not accept after all
+// retries.
+type DeadLetter struct {
+	Sink         string       `json:"sink"`
+	Notification Notification `json:"notification"`
+	Error        string       `json:"error"`
+	Attempts     int          `json:"attempts"`
+	FailedAt     time.Time    `json:"failed_at"`
+}
@@ -5,14 +5,34 @@ type Profile struct {
 	displayName    string
 	description    string
 	prompts        PromptConfig
+	selection      SelectionConfig
+	watch          []WatchRule
 	sourceGroups   map[string][]string
 	defaultSources []string
 	active         bool
 }
 
 type PromptConfig struct {
-	Summarizer string `yaml:"summarizer"`
-	Suggester  string `yaml:"suggester"`
+	Summarizer string `yaml:"summarizer" json:"summarizer"`
+	Suggester  string `yaml:"suggester" json:"suggester"`
+	Tagger     string `yaml:"tagger" json:"tagger,omitempty"`
+	Outliner   string `yaml:"outliner" json:"outliner,omitempty"`
+	Drafter    string `yaml:"drafter" json:"drafter,omitempty"`
+	Namer      string `yaml:"namer" json:"namer,omitempty"`
+}
+
+// SelectionConfig decides which collected trends are given to the
+// suggester. Zero values leave the corresponding filter off.
+type SelectionConfig struct {
+	// Window is how far back to look, as a Go duration such as "72h".
+	Window       string         `yaml:"window" json:"window,omitempty"`
+	MinScore     int            `yaml:"min_score" json:"min_score,omitempty"`
+	StarredFirst bool           `yaml:"starred_first" json:"starred_first,omitempty"`
+	Limit        int            `yaml:"limit" json:"limit,omitempty"`
+	SourceQuotas map[string]int `yaml:"source_quotas" json:"source_quotas,omitempty"`
+	// Keywords boost matching trends in addition to the words of the
+	// profile description.
+	Keywords []string `yaml:"keywords" json:"keywords,omitempty"`
 }
 
 func NewProfile(name, displayName string) *Profile {
@@ -29,12 +49,16 @@ func (p *Profile) Name() string                      { return p.name }
 func (p *Profile) DisplayName() string               { return p.displayName }
 func (p *Profile) Description() string               { return p.description }
 func (p *Profile) Prompts() PromptConfig             { return p.prompts }
+func (p *Profile) Selection() SelectionConfig        { return p.selection }
+func (p *Profile) Watch() []WatchRule                { return p.watch }
 func (p *Profile) SourceGroups() map[string][]string { return p.sourceGroups }
 func (p *Profile) DefaultSources() []string          { return p.defaultSources }
 func (p *Profile) Active() bool                      { return p.active }
 
 func (p *Profile) SetDescription(d string)                { p.description = d }
 func (p *Profile) SetPrompts(pr PromptConfig)             { p.prompts = pr }
+func (p *Profile) SetSelection(sc SelectionConfig)        { p.selection = sc }
+func (p *Profile) SetWatch(w []WatchRule)                 { p.watch = w }
 func (p *Profile) SetSourceGroups(sg map[string][]string) { p.sourceGroups = sg }
 func (p *Profile) SetDefaultSources(ds []string)          { p.defaultSources = ds }
 func (p *Profile) SetActive(a bool)                       { p.active = a }
@@ -45,6 +69,8 @@ func (p *Profile) ToDTO() *ProfileDTO {
 		DisplayName:    p.displayName,
 		Description:    p.description,
 		Prompts:        p.prompts,
+		Selection:      p.selection,
+		Watch:          p.watch,
 		SourceGroups:   p.sourceGroups,
 		DefaultSources: p.defaultSources,
 		Active:         p.active,
@@ -56,6 +82,8 @@ type ProfileDTO struct {
 	DisplayName    string              `yaml:"display_name" json:"display_name"`
 	Description    string              `yaml:"description" json:"description"`
 	Prompts        PromptConfig        `yaml:"prompts" json:"prompts"`
+	Selection      SelectionConfig     `yaml:"selection" json:"selection"`
+	Watch          []WatchRule         `yaml:"watch" json:"watch,omitempty"`
 	SourceGroups   map[string][]string `yaml:"source_groups" json:"source_groups"`
 	DefaultSources []string            `yaml:"default_sources" json:"default_sources"`
 	Active         bool                `yaml:"active" json:"active"`
@@ -65,6 +93,8 @@ func ProfileFromDTO(dto *ProfileDTO) *Profile {
 	p := NewProfile(dto.Name, dto.DisplayName)
 	p.description = dto.Description
 	p.prompts = dto.Prompts
+	p.selection = dto.Selection
+	p.watch = dto.Watch
 	p.sourceGroups = dto.SourceGroups
 	p.defaultSources = dto.DefaultSources
 	p.active = dto.Active
@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type SuggestionStatus string
+
+const (
+	SuggestionNew      SuggestionStatus = "new"
+	SuggestionAccepted SuggestionStatus = "accepted"
+	SuggestionRejected SuggestionStatus = "rejected"
+	SuggestionWritten  SuggestionStatus = "written"
+)
+
+func ParseSuggestionStatus(s string) (SuggestionStatus, error) {
+	switch status := SuggestionStatus(strings.ToLower(strings.TrimSpace(s))); status {
+	case SuggestionNew, SuggestionAccepted, SuggestionRejected, SuggestionWritten:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown suggestion status %q", s)
+}
+
+// Suggestion is a blog post idea proposed by the agent and the author's
+// verdict on it.
+type Suggestion struct {
+	id          string
+	profile     string
+	title       string
+	description string
+	score       float64
+	topics      []string
+	status      SuggestionStatus
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+// NewSuggestion derives the ID from the profile and normalised title, so the
+// same idea proposed twice maps to the same suggestion.
+func NewSuggestion(profile, title string) *Suggestion {
+	now := time.Now().UTC()
+	return &Suggestion{
+		id:        SuggestionID(profile, title),
+		profile:   profile,
+		title:     title,
+		topics:    []string{},
+		status:    SuggestionNew,
+		createdAt: now,
+		updatedAt: now,
+	}
+}
+
+func SuggestionID(profile, title string) string {
+	sum := sha1.Sum([]byte(profile + "\x00" + NormalizeTitle(title)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// NormalizeTitle lowercases a title and reduces it to letters and digits
+// separated by single spaces.
+func NormalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func (s *Suggestion) ID() string               { return s.id }
+func (s *Suggestion) Profile() string          { return s.profile }
+func (s *Suggestion) Title() string            { return s.title }
+func (s *Suggestion) Description() string      { return s.description }
+func (s *Suggestion) Score() float64           { return s.score }
+func (s *Suggestion) Topics() []string         { return s.topics }
+func (s *Suggestion) Status() SuggestionStatus { return s.status }
+func (s *Suggestion) CreatedAt() time.Time     { return s.createdAt }
+func (s *Suggestion) UpdatedAt() time.Time     { return s.updatedAt }
+
+func (s *Suggestion) SetDescription(d string) { s.description = d }
+func (s *Suggestion) SetScore(score float64)  { s.score = score }
+func (s *Suggestion) SetTopics(t []string)    { s.topics = t }
+
+func (s *Suggestion) SetStatus(status SuggestionStatus) {
+	s.status = status
+	s.updatedAt = time.Now().UTC()
+}
+
+func (s *Suggestion) ToDTO() *SuggestionDTO {
+	return &SuggestionDTO{
+		ID:          s.id,
+		Profile:     s.profile,
+		Title:       s.title,
+		Description: s.description,
+		Score:       s.score,
+		Topics:      s.topics,
+		Status:      s.status,
+		CreatedAt:   s.createdAt,
+		UpdatedAt:   s.updatedAt,
+	}
+}
+
+type SuggestionDTO struct {
+	ID          string           `json:"id"`
+	Profile     string           `json:"profile"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Score       float64          `json:"score"`
+	Topics      []string         `json:"topics"`
+	Status      SuggestionStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+func SuggestionFromDTO(dto *SuggestionDTO) *Suggestion {
+	s := &Suggestion{
+		id:          dto.ID,
+		profile:     dto.Profile,
+		title:       dto.Title,
+		description: dto.Description,
+		score:       dto.Score,
+		topics:      dto.Topics,
+		status:      dto.Status,
+		createdAt:   dto.CreatedAt,
+		updatedAt:   dto.UpdatedAt,
+	}
+	if s.topics == nil {
+		s.topics = []string{}
+	}
+	return s
+}
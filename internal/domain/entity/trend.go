@@ -19,6 +19,18 @@ type Trend struct {
 	collectedAt time.Time
 	starred     bool
 	metadata    map[string]any
+	summaries   []SummaryRecord
+}
+
+// SummaryRecord is an LLM-generated summary together with what produced it,
+// so a stored summary can be traced back and regenerated when the prompt or
+// model changes.
+type SummaryRecord struct {
+	Text          string    `json:"text"`
+	Provider      string    `json:"provider"`
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"prompt_version"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 func NewTrend(id, title, url string) *Trend {
@@ -32,20 +44,21 @@ func NewTrend(id, title, url string) *Trend {
 	}
 }
 
-func (t *Trend) ID() string               { return t.id }
-func (t *Trend) Title() string            { return t.title }
-func (t *Trend) URL() string              { return t.url }
-func (t *Trend) Summary() string          { return t.summary }
-func (t *Trend) Score() int               { return t.score }
-func (t *Trend) Author() string           { return t.author }
-func (t *Trend) Source() string           { return t.source }
-func (t *Trend) SourceID() string         { return t.sourceID }
-func (t *Trend) Category() string         { return t.category }
-func (t *Trend) Tags() []string           { return t.tags }
-func (t *Trend) Timestamp() time.Time     { return t.timestamp }
-func (t *Trend) CollectedAt() time.Time   { return t.collectedAt }
-func (t *Trend) Starred() bool            { return t.starred }
-func (t *Trend) Metadata() map[string]any { return t.metadata }
+func (t *Trend) ID() string                 { return t.id }
+func (t *Trend) Title() string              { return t.title }
+func (t *Trend) URL() string                { return t.url }
+func (t *Trend) Summary() string            { return t.summary }
+func (t *Trend) Score() int                 { return t.score }
+func (t *Trend) Author() string             { return t.author }
+func (t *Trend) Source() string             { return t.source }
+func (t *Trend) SourceID() string           { return t.sourceID }
+func (t *Trend) Category() string           { return t.category }
+func (t *Trend) Tags() []string             { return t.tags }
+func (t *Trend) Timestamp() time.Time       { return t.timestamp }
+func (t *Trend) CollectedAt() time.Time     { return t.collectedAt }
+func (t *Trend) Starred() bool              { return t.starred }
+func (t *Trend) Metadata() map[string]any   { return t.metadata }
+func (t *Trend) Summaries() []SummaryRecord { return t.summaries }
 
 func (t *Trend) SetSummary(s string)             { t.summary = s }
 func (t *Trend) SetScore(s int)                  { t.score = s }
@@ -58,6 +71,28 @@ func (t *Trend) SetTimestamp(ts time.Time)       { t.timestamp = ts }
 func (t *Trend) SetStarred(s bool)               { t.starred = s }
 func (t *Trend) SetMetadata(key string, val any) { t.metadata[key] = val }
 func (t *Trend) AddTag(tag string)               { t.tags = append(t.tags, tag) }
+func (t *Trend) SetSummaries(s []SummaryRecord)  { t.summaries = s }
+
+// LatestSummary returns the most recent LLM summary, or nil if the trend has
+// not been summarised.
+func (t *Trend) LatestSummary() *SummaryRecord {
+	if len(t.summaries) == 0 {
+		return nil
+	}
+	return &t.summaries[len(t.summaries)-1]
+}
+
+// AddSummary appends a summary, keeping at most keep records (oldest are
+// dropped). keep <= 0 keeps only the new one.
+func (t *Trend) AddSummary(rec SummaryRecord, keep int) {
+	if keep <= 0 {
+		keep = 1
+	}
+	t.summaries = append(t.summaries, rec)
+	if len(t.summaries) > keep {
+		t.summaries = append([]SummaryRecord(nil), t.summaries[len(t.summaries)-keep:]...)
+	}
+}
 
 func (t *Trend) ToDTO() *TrendDTO {
 	return &TrendDTO{
@@ -75,24 +110,26 @@ func (t *Trend) ToDTO() *TrendDTO {
 		CollectedAt: t.collectedAt,
 		Starred:     t.starred,
 		Metadata:    t.metadata,
+		Summaries:   t.summaries,
 	}
 }
 
 type TrendDTO struct {
-	ID          string         `json:"id"`
-	Title       string         `json:"title"`
-	URL         string         `json:"url"`
-	Summary     string         `json:"summary,omitempty"`
-	Score       int            `json:"score,omitempty"`
-	Author      string         `json:"author,omitempty"`
-	Source      string         `json:"source"`
-	SourceID    string         `json:"source_id"`
-	Category    string         `json:"category,omitempty"`
-	Tags        []string       `json:"tags,omitempty"`
-	Timestamp   time.Time      `json:"timestamp"`
-	CollectedAt time.Time      `json:"collected_at"`
-	Starred     bool           `json:"starred"`
-	Metadata    map[string]any `json:"metadata,omitempty"`
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	URL         string          `json:"url"`
+	Summary     string          `json:"summary,omitempty"`
+	Score       int             `json:"score,omitempty"`
+	Author      string          `json:"author,omitempty"`
+	Source      string          `json:"source"`
+	SourceID    string          `json:"source_id"`
+	Category    string          `json:"category,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Timestamp   time.Time       `json:"timestamp"`
+	CollectedAt time.Time       `json:"collected_at"`
+	Starred     bool            `json:"starred"`
+	Metadata    map[string]any  `json:"metadata,omitempty"`
+	Summaries   []SummaryRecord `json:"summaries,omitempty"`
 }
 
 func TrendFromDTO(dto *TrendDTO) *Trend {
@@ -108,6 +145,7 @@ func TrendFromDTO(dto *TrendDTO) *Trend {
 	t.collectedAt = dto.CollectedAt
 	t.starred = dto.Starred
 	t.metadata = dto.Metadata
+	t.summaries = dto.Summaries
 	if t.metadata == nil {
 		t.metadata = make(map[string]any)
 	}
@@ -11,4 +11,12 @@ var (
 	ErrProfileNotFound  = errors.New("profile not found")
 	ErrSourceNotFound   = errors.New("source not found")
 	ErrTrendNotFound    = errors.New("trend not found")
+	ErrBudgetExceeded   = errors.New("llm budget exceeded")
+	ErrLLMAuth          = errors.New("llm authentication failed")
+	ErrLLMRateLimited   = errors.New("llm rate limited")
+	ErrLLMContextLength = errors.New("llm context length exceeded")
+	ErrLLMUnavailable   = errors.New("llm provider unavailable")
+	ErrLLMInvalidOutput = errors.New("llm response does not match the expected format")
+	ErrNoDraftSources   = errors.New("no trends to draft from")
+	ErrInvalidRequest   = errors.New("invalid request")
 )
@@ -1,12 +1,14 @@
 package event
 
 type SimpleDispatcher struct {
-	handlers map[string][]EventHandler
+	handlers     map[string][]EventHandler
+	syncHandlers map[string][]EventHandler
 }
 
 func NewDispatcher() *SimpleDispatcher {
 	return &SimpleDispatcher{
-		handlers: make(map[string][]EventHandler),
+		handlers:     make(map[string][]EventHandler),
+		syncHandlers: make(map[string][]EventHandler),
 	}
 }
 
@@ -14,7 +16,16 @@ func (d *SimpleDispatcher) Subscribe(eventType string, handler EventHandler) {
 	d.handlers[eventType] = append(d.handlers[eventType], handler)
 }
 
+// SubscribeSync registers a handler that runs in the dispatching goroutine,
+// so it sees events in the order they were dispatched. It must not block.
+func (d *SimpleDispatcher) SubscribeSync(eventType string, handler EventHandler) {
+	d.syncHandlers[eventType] = append(d.syncHandlers[eventType], handler)
+}
+
 func (d *SimpleDispatcher) Dispatch(event Event) {
+	for _, h := range d.syncHandlers[event.Type()] {
+		h(event)
+	}
 	if handlers, ok := d.handlers[event.Type()]; ok {
 		for _, h := range handlers {
 			go h(event)
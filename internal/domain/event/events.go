@@ -12,29 +12,79 @@ type EventDispatcher interface {
 
 type EventHandler func(event Event)
 
+// SyncSubscriber is implemented by dispatchers that can run a handler in
+// order, in the dispatching goroutine.
+type SyncSubscriber interface {
+	SubscribeSync(eventType string, handler EventHandler)
+}
+
 type TrendCollectedEvent struct {
-	TrendID   string
-	SourceID  string
-	Timestamp string
+	TrendID   string `json:"trend_id"`
+	SourceID  string `json:"source_id"`
+	Timestamp string `json:"timestamp"`
 }
 
 func (e *TrendCollectedEvent) Type() string           { return "trend.collected" }
 func (e *TrendCollectedEvent) EventTimestamp() string { return e.Timestamp }
 
 type CollectionStartedEvent struct {
-	JobID     string
-	Profile   string
-	Timestamp string
+	JobID     string `json:"job_id"`
+	Profile   string `json:"profile"`
+	Timestamp string `json:"timestamp"`
 }
 
 func (e *CollectionStartedEvent) Type() string           { return "collection.started" }
 func (e *CollectionStartedEvent) EventTimestamp() string { return e.Timestamp }
 
+// CollectionProgressEvent is dispatched after each source of a collection.
+type CollectionProgressEvent struct {
+	JobID    string `json:"job_id"`
+	Profile  string `json:"profile"`
+	SourceID string `json:"source_id"`
+	// Done of Total sources have been collected.
+	Done       int    `json:"done"`
+	Total      int    `json:"total"`
+	ItemsCount int    `json:"items_count"`
+	Error      string `json:"error,omitempty"`
+	Timestamp  string `json:"timestamp"`
+}
+
+func (e *CollectionProgressEvent) Type() string           { return "collection.progress" }
+func (e *CollectionProgressEvent) EventTimestamp() string { return e.Timestamp }
+
 type CollectionCompletedEvent struct {
-	JobID      string
-	ItemsCount int
-	Timestamp  string
+	JobID      string `json:"job_id"`
+	Profile    string `json:"profile"`
+	ItemsCount int    `json:"items_count"`
+	// NewCount is how many of the items were not stored before.
+	NewCount  int      `json:"new_count"`
+	Errors    []string `json:"errors,omitempty"`
+	Timestamp string   `json:"timestamp"`
 }
 
 func (e *CollectionCompletedEvent) Type() string           { return "collection.completed" }
 func (e *CollectionCompletedEvent) EventTimestamp() string { return e.Timestamp }
+
+// AlertRaisedEvent is dispatched when a watch rule matches a new trend.
+type AlertRaisedEvent struct {
+	AlertID   string `json:"alert_id"`
+	Profile   string `json:"profile"`
+	Rule      string `json:"rule"`
+	TrendID   string `json:"trend_id"`
+	Title     string `json:"title"`
+	URL       string `json:"url,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (e *AlertRaisedEvent) Type() string           { return "alert.raised" }
+func (e *AlertRaisedEvent) EventTimestamp() string { return e.Timestamp }
+
+// TrendStarredEvent is dispatched when a trend is starred or unstarred.
+type TrendStarredEvent struct {
+	TrendID   string `json:"trend_id"`
+	Starred   bool   `json:"starred"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (e *TrendStarredEvent) Type() string           { return "trend.starred" }
+func (e *TrendStarredEvent) EventTimestamp() string { return e.Timestamp }
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+
+	"r3f-trends/internal/domain/entity"
 )
 
 type TopicSuggestion struct {
@@ -11,8 +13,27 @@ type TopicSuggestion struct {
 	Topics      []string `json:"topics"`
 }
 
+type SuggestRequest struct {
+	Prompt   string
+	TrendIDs []string
+}
+
+type OutlineRequest struct {
+	Prompt   string
+	TrendIDs []string
+}
+
+type TrendTags struct {
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+}
+
 type Agent interface {
 	Name() string
-	Summarize(ctx context.Context, content string) (string, error)
-	Suggest(ctx context.Context, trends []string, promptTemplate string) ([]TopicSuggestion, error)
+	Model() string
+	Summarize(ctx context.Context, prompt string) (string, error)
+	Suggest(ctx context.Context, req SuggestRequest) ([]TopicSuggestion, error)
+	Tag(ctx context.Context, prompt string) (*TrendTags, error)
+	Outline(ctx context.Context, req OutlineRequest) (*entity.Outline, error)
+	Draft(ctx context.Context, prompt string) (string, error)
 }